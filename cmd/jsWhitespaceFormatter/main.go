@@ -1,24 +1,51 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pakut2/w-format/internal/formatter"
 	"github.com/pakut2/w-format/internal/utilities"
 	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler"
+	"github.com/pakut2/w-format/pkg/whitespace"
 )
 
 type CommandLineArgs struct {
 	sourceFilePath string
 	formatFilePath utilities.Optional[string]
 	outputFilePath utilities.Optional[string]
+	heapUsage      bool
+	optimization   int
+	stats          bool
 }
 
+const (
+	runCommand         = "run"
+	disassembleCommand = "disasm"
+
+	whitespaceExtension         = ".ws"
+	whitespaceAssemblyExtension = ".wsa"
+)
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case runCommand:
+			runWhitespace(parseFileCommandArgs(runCommand, "Run a Whitespace program, or a file formatted with one. Reading a char past the end of the input stores -1", os.Args[2:]))
+
+			return
+		case disassembleCommand:
+			disassembleWhitespace(parseFileCommandArgs(disassembleCommand, "Print Whitespace instructions of a program, or a file formatted with one, with their byte offsets", os.Args[2:]))
+
+			return
+		}
+	}
+
 	args := parseCommandLineArgs()
 
 	sourceFile, err := os.Open(args.sourceFilePath)
@@ -27,9 +54,60 @@ func main() {
 	}
 	defer sourceFile.Close()
 
-	lexer := jsWhitespaceTranspiler.NewLexer(sourceFile)
-	parsedSource := jsWhitespaceTranspiler.NewParser(lexer).ParseProgram()
-	whitespace := jsWhitespaceTranspiler.NewTranspiler().TranspileProgram(parsedSource)
+	var instructions []whitespace.Instruction
+
+	switch filepath.Ext(args.sourceFilePath) {
+	case whitespaceAssemblyExtension:
+		instructions, err = whitespace.Assemble(sourceFile)
+		if err != nil {
+			panic(fmt.Sprintf("cannot assemble file: %q, error: %v", args.sourceFilePath, err))
+		}
+	case whitespaceExtension:
+		instructions, err = whitespace.Parse(sourceFile)
+		if err != nil {
+			panic(fmt.Sprintf("cannot parse Whitespace file: %q, error: %v", args.sourceFilePath, err))
+		}
+	default:
+		source, err := io.ReadAll(sourceFile)
+		if err != nil {
+			panic(fmt.Sprintf("cannot read file: %q, error: %v", args.sourceFilePath, err))
+		}
+
+		lexer := jsWhitespaceTranspiler.NewNamedLexer(bytes.NewReader(source), args.sourceFilePath)
+
+		parsedSource, err := jsWhitespaceTranspiler.NewParser(lexer).ParseProgram()
+		if err != nil {
+			exitWithDiagnostics(err, string(source))
+		}
+
+		foldedSource := jsWhitespaceTranspiler.NewConstantFolder().FoldProgram(parsedSource)
+
+		transpiler := jsWhitespaceTranspiler.NewTranspiler()
+
+		transpiledSource, err := transpiler.TranspileProgram(foldedSource)
+		if err != nil {
+			exitWithDiagnostics(err, string(source))
+		}
+
+		if args.heapUsage {
+			heapUsage := transpiler.HeapUsage()
+
+			fmt.Fprintf(
+				os.Stderr,
+				"peak heap usage: %d global heap address(es), %d heap address(es) per call frame\n",
+				heapUsage.GlobalHeapAddresses,
+				heapUsage.LargestFrameSize,
+			)
+		}
+
+		instructions = transpiledSource.Instructions()
+	}
+
+	instructions = whitespace.Optimize(instructions, args.optimization)
+
+	if err = whitespace.Validate(instructions); err != nil {
+		panic(fmt.Sprintf("invalid Whitespace program: %q, error: %v", args.sourceFilePath, err))
+	}
 
 	var formatTarget io.Reader
 	if args.formatFilePath.Valid {
@@ -57,17 +135,102 @@ func main() {
 		formatOutput = os.Stdout
 	}
 
-	formatter.NewFormatter(formatTarget, whitespace.Instructions(), formatOutput).Format()
+	whitespaceFormatter := formatter.NewFormatter(formatTarget, instructions, formatOutput)
+
+	if err = whitespaceFormatter.Format(); err != nil {
+		panic(fmt.Sprintf("cannot format file, error: %v", err))
+	}
+
+	if args.stats {
+		printFormatStats(whitespaceFormatter.Stats())
+	}
 
 	if args.outputFilePath.Valid {
 		fmt.Printf("output saved to %q\n", args.outputFilePath.Value)
 	}
 }
 
+func printFormatStats(stats formatter.Stats) {
+	fmt.Fprintf(
+		os.Stderr,
+		"program: %d Whitespace token(s), format file: %d whitespace position(s) (%d space(s) and tab(s), %d line end(s))\n",
+		stats.ProgramTokens,
+		stats.SpacePositions+stats.LineEndPositions,
+		stats.SpacePositions,
+		stats.LineEndPositions,
+	)
+
+	if stats.AppendedTokens > 0 {
+		fmt.Fprintf(os.Stderr, "%d token(s) did not fit and were appended after the end of the format file\n", stats.AppendedTokens)
+	} else {
+		fmt.Fprintf(os.Stderr, "the program fits in the format file, %d no-op token(s) of padding\n", stats.PaddingTokens)
+	}
+}
+
+func exitWithDiagnostics(err error, source string) {
+	if diagnostics, ok := err.(jsWhitespaceTranspiler.Diagnostics); ok {
+		fmt.Fprintln(os.Stderr, diagnostics.Render(source))
+	} else {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(1)
+}
+
+func runWhitespace(sourceFilePath string) {
+	sourceFile, err := os.Open(sourceFilePath)
+	if err != nil {
+		panic(fmt.Sprintf("cannot open file: %q, error: %v", sourceFilePath, err))
+	}
+	defer sourceFile.Close()
+
+	vm, err := whitespace.NewVirtualMachineFromSource(sourceFile, os.Stdin, os.Stdout)
+	if err != nil {
+		panic(fmt.Sprintf("cannot load Whitespace program: %q, error: %v", sourceFilePath, err))
+	}
+
+	if err = vm.Run(); err != nil {
+		panic(fmt.Sprintf("Whitespace runtime error: %v", err))
+	}
+}
+
+func disassembleWhitespace(sourceFilePath string) {
+	sourceFile, err := os.Open(sourceFilePath)
+	if err != nil {
+		panic(fmt.Sprintf("cannot open file: %q, error: %v", sourceFilePath, err))
+	}
+	defer sourceFile.Close()
+
+	if err = whitespace.Disassemble(sourceFile, os.Stdout); err != nil {
+		panic(fmt.Sprintf("cannot disassemble Whitespace program: %q, error: %v", sourceFilePath, err))
+	}
+}
+
+func parseFileCommandArgs(command string, description string, commandArgs []string) string {
+	commandFlags := flag.NewFlagSet(command, flag.ExitOnError)
+	commandFlags.Usage = func() {
+		fmt.Fprintf(commandFlags.Output(), "Usage: %s %s <file>\n\n%s\n", os.Args[0], command, description)
+	}
+
+	if err := commandFlags.Parse(commandArgs); err != nil {
+		panic(err)
+	}
+
+	if commandFlags.NArg() != 1 {
+		commandFlags.Usage()
+		os.Exit(2)
+	}
+
+	return commandFlags.Arg(0)
+}
+
 func parseCommandLineArgs() CommandLineArgs {
-	sourceFilePath := flag.String("source-file", "", "Whitespace transpilation source file path")
+	sourceFilePath := flag.String("source-file", "", "Whitespace transpilation source file path. Files with the .ws extension are read as Whitespace, files with the .wsa extension as Whitespace assembly")
 	formatFilePath := flag.String("format-file", "", "(Optional) Path to file to be formatted with the generated Whitespace. If not provided, outputs Whitespace only")
 	outputFilePath := flag.String("output-file", "", "(Optional) Output file path. If not provided, outputs to stdout")
+	heapUsage := flag.Bool("heap-usage", false, "(Optional) Report the peak number of heap addresses taken by variables and constants of a transpiled Javascript source")
+	optimization := flag.Int("O", whitespace.NO_OPTIMIZATION, "(Optional) Optimization level of the generated Whitespace: 0 - none, 1 - local rewrites, 2 - also control flow, 3 - also shortest encoding")
+	stats := flag.Bool("stats", false, "(Optional) Report the number of Whitespace tokens of the program and the whitespace positions of the format file")
 	flag.Parse()
 
 	if *sourceFilePath == "" {
@@ -94,5 +257,8 @@ func parseCommandLineArgs() CommandLineArgs {
 		sourceFilePath: *sourceFilePath,
 		formatFilePath: parsedFormatTargetFilePath,
 		outputFilePath: parsedFormatOutputFilePath,
+		heapUsage:      *heapUsage,
+		optimization:   *optimization,
+		stats:          *stats,
 	}
 }
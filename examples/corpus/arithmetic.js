@@ -0,0 +1,11 @@
+console.log(2 + 2 * 2);
+console.log((2 + 2) * 2);
+console.log(10 / 2, 17 % 5, 3 - 10);
+console.log(-7 * 6, -(4 - 9));
+
+let a = 12;
+let b = 5;
+console.log(a + b, a - b, a * b, a % b);
+
+a = a * a - b;
+console.log(a);
@@ -0,0 +1,69 @@
+let primes = [];
+
+for (let candidate = 2; primes.length < 10; candidate++) {
+    let isPrime = 1;
+
+    for (let p = 0; p < primes.length; p++) {
+        if (candidate % primes[p] === 0) {
+            isPrime = 0;
+            break;
+        }
+    }
+
+    if (isPrime === 1) {
+        primes.push(candidate);
+    }
+}
+
+for (let i = 0; i < primes.length; i++) {
+    console.log(i, primes[i]);
+}
+
+let values = [5, 3, 8, 1, 9, 2];
+
+for (let pass = 0; pass < values.length; pass++) {
+    for (let j = 0; j < values.length - 1 - pass; j++) {
+        if (values[j] > values[j + 1]) {
+            let swap = values[j];
+            values[j] = values[j + 1];
+            values[j + 1] = swap;
+        }
+    }
+}
+
+let sorted = "";
+
+for (let k = 0; k < values.length; k++) {
+    sorted = sorted + "#";
+    console.log(values[k], sorted);
+}
+
+let words = ["alpha", "beta"];
+words.push("gamma");
+words[0] = words[0] + "!";
+console.log(words[0], words[1], words[2], words.length);
+
+let alias = words;
+alias.push("delta");
+console.log(words.length, words[3]);
+
+let grid = [[1, 2], [3, 4]];
+grid.push([5, 6]);
+grid[2][1] = grid[0][0] + grid[1][1];
+console.log(grid[2][0], grid[2][1], grid.length, grid[1].length);
+
+let counts = [0];
+let pushed = counts.push(7);
+console.log(pushed, counts[counts.length - 1]);
+
+function fill(size) {
+    let squares = [];
+
+    for (let n = 0; n < size; n++) {
+        squares.push(n * n);
+    }
+
+    return squares[size - 1] + squares.length;
+}
+
+console.log(fill(12));
@@ -0,0 +1,38 @@
+console.log(true, false);
+console.log(2 > 1, 2 < 1, 3 === 3, 3 !== 3, 4 >= 5, 4 <= 5);
+console.log(!true, !false, !0, !7);
+
+let ready = false;
+console.log(ready);
+
+ready = 10 % 2 === 0;
+console.log("ready:", ready);
+
+let copy = ready;
+copy = !copy;
+console.log(ready, copy, ready === copy, ready !== copy);
+
+console.log(true && false, true || false, 1 < 2 && 2 < 3, 1 > 2 || 2 > 3);
+
+const flags = [true, false];
+flags.push(1 === 1);
+flags[1] = !flags[1];
+console.log(flags[0], flags[1], flags[2], flags.length);
+
+let count = 0;
+let running = true;
+
+while (running) {
+    count = count + 1;
+    running = count < 3;
+}
+
+console.log(count, running);
+
+if ("text") {
+    console.log("non-empty strings are truthy");
+}
+
+if (!"") {
+    console.log("empty strings are falsy");
+}
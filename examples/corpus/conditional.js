@@ -0,0 +1,36 @@
+for (let i = 1; i <= 15; i++) {
+    console.log(i % 15 === 0 ? "FizzBuzz" : i % 3 === 0 ? "Fizz" : i % 5 === 0 ? "Buzz" : "-");
+}
+
+let a = 7;
+let b = 12;
+let larger = a > b ? a : b;
+console.log("larger:", larger);
+
+let sign = a - b < 0 ? -1 : a === b ? 0 : 1;
+console.log("sign:", sign);
+
+const even = a % 2 === 0 ? true : false;
+console.log(even, !even ? "odd" : "even");
+
+let label = "";
+
+for (let i = 0; i < 4; i++) {
+    label = label + (i > 0 ? ", " : "") + (i % 2 === 0 ? "even" : "odd");
+}
+
+console.log(label);
+
+let small = [1, 2];
+let big = [10, 20, 30];
+let chosen = small.length > 2 ? small : big;
+console.log(chosen[chosen.length - 1], (b > a ? small : big).length);
+
+function absolute(n) {
+    return n < 0 ? -n : n;
+}
+
+console.log(absolute(-5), absolute(3), absolute(0) === 0 ? "zero" : "non-zero");
+
+let nested = a > 5 ? (b > 10 ? "both" : "a only") : "neither";
+console.log(nested);
@@ -0,0 +1,25 @@
+let temperature = 23;
+
+if (temperature > 30) {
+    console.log("hot");
+} else {
+    console.log("not hot");
+}
+
+if (temperature >= 20 && temperature <= 25) {
+    console.log("pleasant");
+}
+
+if (temperature < 0 || temperature === 23) {
+    console.log("freezing or exactly 23");
+}
+
+if (!(temperature !== 23)) {
+    console.log("still 23");
+}
+
+if (temperature % 2) {
+    console.log("odd");
+} else {
+    console.log("even");
+}
@@ -0,0 +1,8 @@
+let a = 1;
+let b = a;
+b = 2;
+console.log(a, b);
+
+let c = b;
+c = c + 10;
+console.log(b, c);
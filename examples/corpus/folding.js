@@ -0,0 +1,68 @@
+const width = 4;
+const height = width * 3 - 2;
+const title = "area" + ": ";
+
+console.log(2 + 2 * 2);
+console.log(title + "w", width, height, width * height);
+console.log(-7 / 7, 9 % 4, 12 / -4, 0 % 3);
+console.log(width > height, !(width === 4), "a" + "b" ? "same" : "other");
+console.log(true && false, 0 || 5, "" || "empty", true !== false);
+
+if (false) {
+  console.log("never");
+}
+
+if (width > 2) {
+  const width = 1;
+  console.log("wide", width);
+} else {
+  console.log("narrow");
+}
+
+if (height < 0) {
+  console.log("negative");
+} else {
+  let shadow = height;
+  shadow += width;
+  console.log(shadow);
+}
+
+while (false) {
+  console.log("never");
+}
+
+for (let i = 0; false; i++) {
+  console.log("never");
+}
+
+const values = [width, height];
+values[width - 4] = height;
+values[1] += 1;
+console.log(values[0], values[1], values.length);
+
+let counter = width;
+counter++;
+counter *= height;
+console.log(counter);
+
+function scale(width) {
+  const factor = 3;
+  return width * factor;
+}
+
+console.log(scale(2), scale(width));
+
+outer: if (true) {
+  console.log("labeled");
+  break outer;
+}
+
+let limit = 2;
+for (const step = 1; limit > 0; limit -= step) {
+  console.log(limit, step);
+}
+
+do {
+  limit++;
+} while (limit < 3 && true);
+console.log(limit);
@@ -0,0 +1,69 @@
+function factorial(n) {
+    if (n <= 1) {
+        return 1;
+    }
+
+    return n * factorial(n - 1);
+}
+
+function fibonacci(n) {
+    if (n < 2) {
+        return n;
+    }
+
+    return fibonacci(n - 1) + fibonacci(n - 2);
+}
+
+function greatestCommonDivisor(a, b) {
+    if (b === 0) {
+        return a;
+    }
+
+    return greatestCommonDivisor(b, a % b);
+}
+
+function printRow(width, height) {
+    for (let i = 0; i < width; i++) {
+        if (i === height) {
+            return;
+        }
+
+        console.log("row", i, "of", width);
+    }
+}
+
+let calls = 0;
+
+function countCall() {
+    calls = calls + 1;
+
+    return calls;
+}
+
+for (let i = 1; i <= 10; i++) {
+    console.log(i, factorial(i));
+}
+
+for (let j = 0; j < 15; j++) {
+    console.log("fibonacci", j, fibonacci(j));
+}
+
+console.log(greatestCommonDivisor(1071, 462));
+console.log(power(2, 10));
+
+printRow(4, 2);
+printRow(2, 5);
+
+countCall();
+countCall();
+console.log("calls", countCall());
+
+function power(base, exponent) {
+    let result = 1;
+
+    for (let k = 0; k < exponent; k++) {
+        result = result * base;
+    }
+
+    return result;
+}
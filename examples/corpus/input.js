@@ -0,0 +1,29 @@
+let name = readline();
+console.log("Hello, " + name + "!");
+
+let count = readInt();
+let total = 0;
+let largest = 0;
+
+for (let i = 0; i < count; i++) {
+    const value = readInt();
+    total = total + value;
+
+    if (i === 0 || value > largest) {
+        largest = value;
+    }
+}
+
+console.log("count:", count, "total:", total, "largest:", largest);
+
+let lines = [];
+let line = readline();
+
+while (line) {
+    lines.push(line);
+    line = readline();
+}
+
+for (let i = lines.length - 1; i >= 0; i--) {
+    console.log(lines[i]);
+}
@@ -0,0 +1,72 @@
+outer: for (let i = 1; i <= 5; i++) {
+    for (let j = 1; j <= 5; j++) {
+        if (j > i) {
+            continue outer;
+        }
+
+        if (i * j > 12) {
+            break outer;
+        }
+
+        console.log(i, "*", j, "=", i * j);
+    }
+}
+
+let found = 0;
+let attempts = 0;
+
+search: while (found === 0) {
+    let k = 0;
+
+    do {
+        k = k + 1;
+        attempts = attempts + 1;
+
+        if (attempts % 7 === 0) {
+            found = attempts;
+            break search;
+        }
+    } while (k < 3);
+}
+
+console.log("found", found);
+
+block: {
+    console.log("before break");
+
+    if (found > 0) {
+        break block;
+    }
+
+    console.log("unreachable");
+}
+
+first: second: for (let n = 0; n < 10; n++) {
+    if (n === 2) {
+        continue first;
+    }
+
+    if (n === 4) {
+        break second;
+    }
+
+    console.log("n", n);
+}
+
+function firstPair(limit) {
+    rows: for (let a = 1; a < limit; a++) {
+        for (let b = 1; b < limit; b++) {
+            if (a + b === limit && a * b > 10) {
+                break rows;
+            }
+
+            if (b > a) {
+                continue rows;
+            }
+        }
+    }
+
+    return limit;
+}
+
+console.log(firstPair(9));
@@ -0,0 +1,57 @@
+let counter = 100;
+
+function bump(step) {
+  let previous = counter;
+  counter = counter + step;
+
+  let unused = step * 2;
+  let doubled = previous * 2;
+
+  return doubled;
+}
+
+let a = 1;
+let b = a + 1;
+console.log(a, b);
+
+let c = 3;
+let d = c * c;
+console.log(d);
+
+let e = "text";
+let f = [e, e + "!"];
+console.log(f[1], f.length);
+
+for (let i = 0; i < 3; i++) {
+  let square = i * i;
+  let label = "i" + (square > 1 ? "!" : "?");
+  console.log(square, label);
+
+  let total = square + bump(i);
+  console.log(total);
+}
+
+let kept = 5;
+
+if (kept > 1) {
+  let kept = 7;
+  let other = kept + 1;
+  console.log(other);
+}
+
+let after = 9;
+console.log(kept, after, counter);
+
+let outer = 0;
+while (outer < 2) {
+  let inner = outer + 10;
+  outer++;
+  let later = inner * 2;
+  console.log(inner, later);
+}
+
+let x = 1;
+let y = 2;
+let z = x + y;
+let w = z * 2;
+console.log(w, counter);
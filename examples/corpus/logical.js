@@ -0,0 +1,42 @@
+let calls = 0;
+
+function touch(value) {
+    calls = calls + 1;
+    return value;
+}
+
+console.log(0 || 5, 3 || 5, 0 && 5, 3 && 5);
+console.log(touch(0) && touch(1), calls);
+console.log(touch(2) || touch(3), calls);
+console.log(touch(4) && touch(0), calls);
+console.log(touch(0) || touch(6), calls);
+
+console.log("" || "fallback", "first" || "second", "" && "never", "a" && "b");
+console.log(true && false, false || true, 1 < 2 && 2 < 3, 1 > 2 || 2 > 3);
+
+let n = 0;
+
+for (let i = 0; i < 10; i++) {
+    if (i > 5 || touch(i) > 2 && i % 2 === 0) {
+        n = n + 1;
+    }
+}
+
+console.log(n, calls);
+
+let items = [];
+let list = items && [7, 8];
+console.log(list[0], list.length);
+
+let name = "";
+let greeting = "Hello, " + (name || "stranger");
+console.log(greeting);
+
+let count = 0;
+let limit = 3;
+
+while (count < limit && touch(count) >= 0) {
+    count = count + 1;
+}
+
+console.log(count, calls);
@@ -0,0 +1,27 @@
+for (let i = 0; i < 5; i++) {
+    console.log(i);
+}
+
+for (let j = 10; j > 0; j--) {
+    if (j === 3) {
+        break;
+    }
+
+    if (j % 2 === 0) {
+        continue;
+    }
+
+    console.log("odd", j);
+}
+
+let total = 0;
+for (let k = 1; k <= 10; k++) {
+    for (let m = 1; m <= k; m++) {
+        if (m > 3) {
+            break;
+        }
+
+        total = total + m;
+    }
+}
+console.log(total);
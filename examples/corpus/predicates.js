@@ -0,0 +1,60 @@
+function isEven(n) {
+    return n % 2 === 0;
+}
+
+function isPrime(n) {
+    if (n < 2) {
+        return false;
+    }
+
+    for (let divisor = 2; divisor * divisor <= n; divisor++) {
+        if (n % divisor === 0) {
+            return false;
+        }
+    }
+
+    return true;
+}
+
+function describe(n, verbose) {
+    if (verbose) {
+        console.log(n, "even", isEven(n), "prime", isPrime(n));
+
+        return;
+    }
+
+    console.log(n, isPrime(n));
+}
+
+function choose(condition, whenTrue, whenFalse) {
+    if (condition) {
+        return whenTrue;
+    }
+
+    return whenFalse;
+}
+
+function all(limit, strict) {
+    if (limit === 0) {
+        return strict;
+    }
+
+    return all(limit - 1, strict) && !isEven(limit * 2 + 1);
+}
+
+for (let i = 0; i < 12; i++) {
+    describe(i, i % 3 === 0);
+}
+
+let primes = 0;
+
+for (let j = 0; j < 50; j++) {
+    if (isPrime(j)) {
+        primes = primes + 1;
+    }
+}
+
+console.log("primes", primes);
+console.log(choose(isEven(4), 10, 20), choose(isPrime(9), 10, 20));
+console.log(all(5, true), all(3, false), !all(2, true));
+console.log(isEven(7) || isPrime(7));
@@ -0,0 +1,10 @@
+let a = 2;
+console.log(a, -a, a);
+
+let b = 0;
+
+if (!b) {
+    console.log("b is still", b);
+}
+
+console.log(-(-a), a);
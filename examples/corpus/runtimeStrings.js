@@ -0,0 +1,54 @@
+let s = "a";
+
+for (let i = 0; i < 5; i++) {
+    s = s + "b";
+    console.log(s);
+}
+
+let line = "";
+let row = 0;
+
+while (row < 4) {
+    line = line + "*";
+    console.log(line, row);
+    row = row + 1;
+}
+
+let copy = s;
+s = "reassigned";
+console.log(copy, s);
+
+let greeting = "Hello";
+greeting = greeting + ", " + "World" + "!";
+console.log(greeting);
+
+let separator = "-";
+let ruler = "";
+
+for (let i = 0; i < 3; i++) {
+    ruler = ruler + separator + separator;
+    separator = separator + "=";
+}
+
+console.log(ruler);
+console.log("" + ruler + "");
+
+let empty = "";
+console.log(empty);
+console.log(empty + empty, "|");
+console.log("zażółć" + " " + greeting);
+
+function stars(count) {
+    let result = "";
+
+    for (let k = 0; k < count; k++) {
+        result = result + "*";
+    }
+
+    console.log(result);
+
+    return count;
+}
+
+stars(3);
+stars(stars(2) + 3);
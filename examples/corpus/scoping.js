@@ -0,0 +1,70 @@
+let total = 0;
+
+for (let i = 0; i < 3; i++) {
+    total = total + i;
+}
+
+for (let i = 10; i > 7; i--) {
+    total = total + i;
+}
+
+console.log(total);
+
+let x = 1;
+
+{
+    let x = 2;
+    console.log(x);
+
+    {
+        let x = "three";
+        console.log(x);
+    }
+
+    x = x + 10;
+    console.log(x);
+}
+
+console.log(x);
+
+if (x === 1) {
+    let message = "inner";
+    console.log(message);
+} else {
+    let message = 0;
+    console.log(message);
+}
+
+let message = "outer";
+console.log(message);
+
+const limit = 4;
+const squares = [];
+
+for (let i = 0; i < limit; i++) {
+    const square = i * i;
+    squares.push(square);
+}
+
+console.log(squares.length, squares[limit - 1]);
+
+let n = 3;
+
+while (n > 0) {
+    let n2 = n * 2;
+    console.log(n2);
+    n = n - 1;
+}
+
+function scoped(n) {
+    let result = 0;
+
+    for (let i = 0; i < n; i++) {
+        let result = i;
+        console.log(result);
+    }
+
+    return result;
+}
+
+console.log(scoped(2));
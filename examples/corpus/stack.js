@@ -0,0 +1,46 @@
+function factorial(n) {
+  if (n < 2) {
+    return 1;
+  }
+
+  return n * factorial(n - 1);
+}
+
+function sum(a, b, c) {
+  return a + b + c;
+}
+
+let base = 7;
+console.log(base + factorial(5) * 2, sum(1, factorial(3), base), "end");
+console.log(sum(sum(1, 2, 3), sum(4, 5, 6), 10 - sum(1, 1, 1)));
+
+let name = "stack";
+let flag = base > 3;
+console.log("a", base, name, flag, 1 + 2, "b", !flag, name + "!", base === 7);
+
+let values = [3, 1, 4, 1, 5];
+let i = 0;
+values[i++] += 10;
+values[++i] -= values[i];
+console.log(values[0], values[1], values[2], i);
+
+let previous = values[3]++;
+let next = ++values[4];
+console.log(previous, next, values[3], values[4]);
+
+let grid = [[1, 2], [3, 4 + base]];
+grid[1][0] *= grid[0][1] + grid[1][1];
+console.log(grid[1][0], grid.length, grid[1].length);
+
+let words = ["x", name, "" + name];
+words.push(flag ? "yes" : "no");
+console.log(words[3], words.length, words[1] + words[2]);
+
+let total = 0;
+for (let k = 0; k < values.length; k++) {
+  total += k % 2 === 0 ? values[k] : -values[k];
+}
+console.log(total, (total && base) || 0, "" || name, name && "");
+
+let deep = 1 + (2 * (3 + (4 * (5 + (6 * (7 + 8))))));
+console.log(deep, -deep, -(deep - 1000));
@@ -0,0 +1,8 @@
+console.log("Hello World");
+console.log('single', "double", `template`);
+console.log("Hello" + " " + "World");
+
+let greeting = "Hi";
+let name = "there";
+console.log(greeting + ", " + name + "!");
+console.log(greeting, name, 42);
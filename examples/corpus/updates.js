@@ -0,0 +1,66 @@
+let a = 10;
+a += 5;
+a -= 3;
+a *= 4;
+a /= 6;
+a %= 5;
+console.log(a);
+
+let i = 0;
+i++;
+i++;
+++i;
+--i;
+i--;
+console.log(i);
+
+let j = 5;
+console.log(j++, j, ++j, j, j--, j, --j, j);
+
+let text = "ab";
+text += "cd";
+text += text;
+console.log(text);
+
+let values = [1, 2, 3];
+values[0] += 10;
+values[1] *= values[2];
+values[2]++;
+++values[2];
+let k = 0;
+values[k++] -= 1;
+console.log(values[0], values[1], values[2], k);
+
+let words = ["x"];
+words[0] += "yz";
+console.log(words[0]);
+
+let total = 0;
+
+for (let n = 0; n < 20; n += 3) {
+    total += n;
+}
+
+console.log(total);
+
+for (let n = 10; n > 0; n = n - 4) {
+    console.log(n);
+}
+
+let countdown = 3;
+
+while (countdown--) {
+    console.log("tick", countdown);
+}
+
+function next(counter) {
+    counter++;
+    return ++counter;
+}
+
+console.log(next(1));
+
+let m = 5;
+console.log(m + m++, m, m-- - m);
+m += m++;
+console.log(m);
@@ -0,0 +1,12 @@
+let a = 1;
+let b = a;
+b = 2;
+console.log(a, b);
+
+let c = 5;
+let d = -c;
+console.log(c, d);
+
+let e = 3;
+e = e + c;
+console.log(e, c);
@@ -0,0 +1,61 @@
+let n = 27;
+let steps = 0;
+
+while (n !== 1) {
+    if (n % 2 === 0) {
+        n = n / 2;
+    } else {
+        n = 3 * n + 1;
+    }
+
+    steps = steps + 1;
+}
+
+console.log("collatz steps", steps);
+
+let digits = 0;
+let number = 90210;
+
+do {
+    digits = digits + 1;
+    number = (number - number % 10) / 10;
+} while (number > 0);
+
+console.log("digits", digits);
+
+let i = 0;
+
+while (true) {
+    i = i + 1;
+
+    if (i % 3 === 0) {
+        continue;
+    }
+
+    if (i > 10) {
+        break;
+    }
+
+    console.log(i);
+}
+
+let once = 0;
+
+do {
+    once = once + 1;
+} while (false);
+
+console.log("once", once);
+
+function sumDigits(value) {
+    let sum = 0;
+
+    while (value > 0) {
+        sum = sum + value % 10;
+        value = (value - value % 10) / 10;
+    }
+
+    return sum;
+}
+
+console.log(sumDigits(987654321));
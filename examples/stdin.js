@@ -0,0 +1,58 @@
+// Node counterparts of the readline() and readInt() built-ins, reading standard input one line at a time:
+// node --require ./examples/stdin.js <js-file-path>
+"use strict";
+
+const fs = require("node:fs");
+const { StringDecoder } = require("node:string_decoder");
+
+const decoder = new StringDecoder("utf8");
+const chunk = Buffer.alloc(4096);
+
+let pending = "";
+let inputEnded = false;
+
+function readInputLine() {
+    while (!pending.includes("\n") && !inputEnded) {
+        let bytesRead;
+
+        try {
+            bytesRead = fs.readSync(0, chunk, 0, chunk.length, null);
+        } catch (error) {
+            if (error.code === "EAGAIN") {
+                continue;
+            }
+
+            if (error.code !== "EOF") {
+                throw error;
+            }
+
+            bytesRead = 0;
+        }
+
+        if (bytesRead === 0) {
+            inputEnded = true;
+            pending += decoder.end();
+        } else {
+            pending += decoder.write(chunk.subarray(0, bytesRead));
+        }
+    }
+
+    const lineEnd = pending.indexOf("\n");
+    const line = lineEnd === -1 ? pending : pending.slice(0, lineEnd);
+
+    pending = lineEnd === -1 ? "" : pending.slice(lineEnd + 1);
+
+    return line;
+}
+
+globalThis.readline = readInputLine;
+
+globalThis.readInt = function readInt() {
+    const line = readInputLine().trim();
+
+    if (!/^[+-]?\d+$/.test(line)) {
+        throw new Error(`cannot parse ${JSON.stringify(line)} as number`);
+    }
+
+    return Number.parseInt(line, 10);
+};
@@ -10,6 +10,18 @@ import (
 	"github.com/pakut2/w-format/pkg/whitespace"
 )
 
+// Stats compares the program with the format file it was placed in
+type Stats struct {
+	ProgramTokens int
+	// Spaces and tabs take one token, line ends take tokens up to a line feed
+	SpacePositions   int
+	LineEndPositions int
+	// Program tokens that did not fit in the format file, written after its end
+	AppendedTokens int
+	// No-op tokens filling the format file after the program
+	PaddingTokens int
+}
+
 type Formatter struct {
 	input  bufio.Reader
 	target bufio.Writer
@@ -18,8 +30,12 @@ type Formatter struct {
 	whitespaceFinalInstructionTokens []whitespace.Token
 	whitespaceTokenIndex             int
 
+	stats Stats
+
 	previousChar rune
 	currentChar  rune
+
+	err error
 }
 
 func NewFormatter(input io.Reader, whitespaceInstructions []whitespace.Instruction, target io.Writer) *Formatter {
@@ -27,11 +43,11 @@ func NewFormatter(input io.Reader, whitespaceInstructions []whitespace.Instructi
 
 	whitespaceInstructionsLength := len(whitespaceInstructions)
 
-	for _, instruction := range whitespaceInstructions[:whitespaceInstructionsLength-1] {
-		f.whitespaceInstructionTokens = append(f.whitespaceInstructionTokens, instruction.Body...)
-	}
+	f.whitespaceInstructionTokens = whitespace.Encode(whitespaceInstructions[:whitespaceInstructionsLength-1])
 
-	f.whitespaceFinalInstructionTokens = whitespaceInstructions[whitespaceInstructionsLength-1].Body
+	f.whitespaceFinalInstructionTokens = whitespaceInstructions[whitespaceInstructionsLength-1].Encode()
+
+	f.stats.ProgramTokens = len(f.whitespaceInstructionTokens) + len(f.whitespaceFinalInstructionTokens)
 
 	f.readChar()
 
@@ -40,11 +56,17 @@ func NewFormatter(input io.Reader, whitespaceInstructions []whitespace.Instructi
 
 func (f *Formatter) readChar() {
 	f.previousChar = f.currentChar
-	f.currentChar = utilities.ReadRune(&f.input)
+
+	char, _, err := utilities.ReadRune(&f.input)
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("cannot read format file, error: %v", err)
+	}
+
+	f.currentChar = char
 }
 
-func (f *Formatter) Format() {
-	for f.currentChar != 0 {
+func (f *Formatter) Format() error {
+	for f.currentChar != 0 && f.err == nil {
 		switch f.currentChar {
 		case ' ', '\t':
 			nextTwoChars, err := utilities.PeekTwoRunes(f.input)
@@ -53,11 +75,15 @@ func (f *Formatter) Format() {
 					f.writeString("\u2007")
 				}
 			} else {
+				f.stats.SpacePositions++
+
 				if err = f.target.WriteByte(byte(f.getNextWhitespaceToken())); err != nil {
 					f.handleOutputError(err)
 				}
 			}
 		case '\n':
+			f.stats.LineEndPositions++
+
 			f.writeString(string(f.getNextWhitespaceTokenUntil(whitespace.LINE_FEED)))
 		case '"', '\'', '`':
 			stringLiteral := f.readString()
@@ -68,6 +94,8 @@ func (f *Formatter) Format() {
 				f.readChar()
 				commentLiteral := f.readComment()
 
+				f.stats.LineEndPositions++
+
 				f.writeString(
 					fmt.Sprintf(
 						"//%s%s",
@@ -92,6 +120,11 @@ func (f *Formatter) Format() {
 		f.readChar()
 	}
 
+	programTokens := f.stats.ProgramTokens - len(f.whitespaceFinalInstructionTokens)
+
+	f.stats.AppendedTokens = max(programTokens-f.whitespaceTokenIndex, 0)
+	f.stats.PaddingTokens = len(f.whitespaceInstructionTokens) - programTokens
+
 	if f.whitespaceTokenIndex < len(f.whitespaceInstructionTokens) {
 		f.writeString(string(f.whitespaceInstructionTokens[f.whitespaceTokenIndex:len(f.whitespaceInstructionTokens)]))
 	}
@@ -101,13 +134,20 @@ func (f *Formatter) Format() {
 	if err := f.target.Flush(); err != nil {
 		f.handleOutputError(err)
 	}
+
+	return f.err
+}
+
+// Stats describes the last call to Format
+func (f *Formatter) Stats() Stats {
+	return f.stats
 }
 
 func (f *Formatter) peekNextWhitespaceToken() whitespace.Token {
 	nextTokenIndex := f.whitespaceTokenIndex + 1
 
 	if nextTokenIndex >= len(f.whitespaceInstructionTokens)-1 {
-		return whitespace.Noop().Body[0]
+		return whitespace.Encode(whitespace.Noop())[0]
 	}
 
 	return f.whitespaceInstructionTokens[nextTokenIndex]
@@ -115,7 +155,7 @@ func (f *Formatter) peekNextWhitespaceToken() whitespace.Token {
 
 func (f *Formatter) getNextWhitespaceToken() whitespace.Token {
 	if f.whitespaceTokenIndex >= len(f.whitespaceInstructionTokens)-1 {
-		f.whitespaceInstructionTokens = append(f.whitespaceInstructionTokens, whitespace.Noop().Body...)
+		f.whitespaceInstructionTokens = append(f.whitespaceInstructionTokens, whitespace.Encode(whitespace.Noop())...)
 	}
 
 	token := f.whitespaceInstructionTokens[f.whitespaceTokenIndex]
@@ -213,5 +253,7 @@ func (f *Formatter) writeString(value string) {
 }
 
 func (f *Formatter) handleOutputError(err error) {
-	panic(fmt.Sprintf("cannot write formatted output, error: %v", err))
+	if f.err == nil {
+		f.err = fmt.Errorf("cannot write formatted output, error: %v", err)
+	}
 }
@@ -0,0 +1,166 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pakut2/w-format/pkg/whitespace"
+)
+
+const hostFile = `type Point = { x: number; y: number };
+
+function distance(a: Point, b: Point): number {
+    const dx = a.x - b.x;
+    const dy = a.y - b.y;
+
+    return Math.sqrt(dx * dx + dy * dy);
+}
+
+const points: Point[] = [{ x: 0, y: 0 }, { x: 3, y: 4 }];
+
+console.log(distance(points[0], points[1]));
+`
+
+func TestFormatShortProgramRuns(t *testing.T) {
+	// console.log(1)
+	instructions := []whitespace.Instruction{
+		whitespace.PushToStack('1'),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack('\n'),
+		whitespace.PrintTopStackChar(),
+		whitespace.EndProgram(),
+	}
+
+	var formatted bytes.Buffer
+
+	NewFormatter(strings.NewReader(hostFile), instructions, &formatted).Format()
+
+	var output bytes.Buffer
+
+	vm, err := whitespace.NewVirtualMachineFromSource(&formatted, strings.NewReader(""), &output)
+	if err != nil {
+		t.Fatalf("cannot load formatted file, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("cannot run formatted file, error: %v", err)
+	}
+
+	if output.String() != "1\n" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "1\n", output.String())
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	// console.log(1)
+	instructions := []whitespace.Instruction{
+		whitespace.PushToStack('1'),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack('\n'),
+		whitespace.PrintTopStackChar(),
+		whitespace.EndProgram(),
+	}
+
+	tests := []struct {
+		name          string
+		formatFile    string
+		expectedStats Stats
+	}{
+		{
+			name:       "program fits",
+			formatFile: hostFile,
+			expectedStats: Stats{
+				ProgramTokens:    29,
+				SpacePositions:   59,
+				LineEndPositions: 12,
+				AppendedTokens:   0,
+				PaddingTokens:    64,
+			},
+		},
+		{
+			name:       "program overflows",
+			formatFile: "const answer = 42;\nconsole.log(answer);\n",
+			expectedStats: Stats{
+				ProgramTokens:    29,
+				SpacePositions:   3,
+				LineEndPositions: 2,
+				AppendedTokens:   14,
+				PaddingTokens:    0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var formatted bytes.Buffer
+
+			formatter := NewFormatter(strings.NewReader(tt.formatFile), instructions, &formatted)
+			if err := formatter.Format(); err != nil {
+				t.Fatalf("cannot format, error: %v", err)
+			}
+
+			if formatter.Stats() != tt.expectedStats {
+				t.Errorf("stats incorrect. expected=%+v, got=%+v", tt.expectedStats, formatter.Stats())
+			}
+		})
+	}
+}
+
+func TestFormatNegativeAndZeroLabels(t *testing.T) {
+	// Jumps to L-1, which prints "-" and jumps back to L0, which prints "0"
+	instructions := []whitespace.Instruction{
+		whitespace.JumpToLabel(-1),
+		whitespace.Label(0),
+		whitespace.PushToStack('0'),
+		whitespace.PrintTopStackChar(),
+		whitespace.EndProgram(),
+		whitespace.Label(-1),
+		whitespace.PushToStack('-'),
+		whitespace.PrintTopStackChar(),
+		whitespace.JumpToLabel(0),
+	}
+
+	if string(whitespace.Label(-1).Encode()) == string(whitespace.Label(0).Encode()) {
+		t.Fatalf("labels L-1 and L0 have the same encoding %q", string(whitespace.Label(0).Encode()))
+	}
+
+	var formatted bytes.Buffer
+
+	if err := NewFormatter(strings.NewReader(hostFile), instructions, &formatted).Format(); err != nil {
+		t.Fatalf("cannot format, error: %v", err)
+	}
+
+	parsedInstructions, err := whitespace.Parse(bytes.NewReader(formatted.Bytes()))
+	if err != nil {
+		t.Fatalf("cannot parse formatted file, error: %v", err)
+	}
+
+	// Padding no-ops are placed before the last instruction
+	lastInstruction := len(instructions) - 1
+
+	for i, instruction := range instructions[:lastInstruction] {
+		if parsedInstructions[i] != instruction {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i+1, instruction, parsedInstructions[i])
+		}
+	}
+
+	if parsedInstructions[len(parsedInstructions)-1] != instructions[lastInstruction] {
+		t.Errorf("last instruction incorrect. expected=%q, got=%q", instructions[lastInstruction], parsedInstructions[len(parsedInstructions)-1])
+	}
+
+	var output bytes.Buffer
+
+	vm, err := whitespace.NewVirtualMachineFromSource(&formatted, strings.NewReader(""), &output)
+	if err != nil {
+		t.Fatalf("cannot load formatted file, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("cannot run formatted file, error: %v", err)
+	}
+
+	if output.String() != "-0" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "-0", output.String())
+	}
+}
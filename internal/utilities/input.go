@@ -8,17 +8,17 @@ import (
 	"unicode/utf8"
 )
 
-func ReadRune(input *bufio.Reader) rune {
-	rune, _, err := input.ReadRune()
+func ReadRune(input *bufio.Reader) (rune, int, error) {
+	rune, size, err := input.ReadRune()
 	if err != nil {
 		if err == io.EOF {
-			return 0
-		} else {
-			panic(fmt.Sprintf("input processing error: %v", err))
+			return 0, 0, nil
 		}
+
+		return 0, 0, fmt.Errorf("input processing error: %v", err)
 	}
 
-	return rune
+	return rune, size, nil
 }
 
 func PeekRune(input bufio.Reader) rune {
@@ -0,0 +1,138 @@
+package jsWhitespaceTranspiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+)
+
+const ERROR = "error"
+
+type Diagnostic struct {
+	File     string
+	Line     int
+	Column   int
+	Offset   int
+	Length   int
+	Severity string
+	Message  string
+}
+
+func newDiagnostic(fileName string, span ast.Span, severity string, format string, args ...any) Diagnostic {
+	return Diagnostic{
+		File:     fileName,
+		Line:     span.Start.Line,
+		Column:   span.Start.Column,
+		Offset:   span.Start.Offset,
+		Length:   span.End.Offset - span.Start.Offset,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (d Diagnostic) Error() string {
+	location := fmt.Sprintf("%s:%d", d.File, d.Line)
+
+	if d.Column > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Column)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+}
+
+func (d Diagnostic) Render(source string) string {
+	lines := strings.Split(source, "\n")
+	if d.Line < 1 || d.Line > len(lines) || d.Column < 1 {
+		return d.Error()
+	}
+
+	line := strings.TrimSuffix(lines[d.Line-1], "\r")
+
+	var caret strings.Builder
+
+	for i, char := range []rune(line) {
+		if i >= d.Column-1 {
+			break
+		}
+
+		if char == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+
+	caret.WriteRune('^')
+
+	if d.Offset >= 0 && d.Offset+d.Length <= len(source) {
+		highlighted, _, _ := strings.Cut(source[d.Offset:d.Offset+d.Length], "\n")
+
+		if highlightedLength := utf8.RuneCountInString(highlighted); highlightedLength > 1 {
+			caret.WriteString(strings.Repeat("~", highlightedLength-1))
+		}
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", d.Error(), line, caret.String())
+}
+
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	messages := make([]string, len(d))
+
+	for i, diagnostic := range d {
+		messages[i] = diagnostic.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (d Diagnostics) Render(source string) string {
+	renderedDiagnostics := make([]string, len(d))
+
+	for i, diagnostic := range d {
+		renderedDiagnostics[i] = diagnostic.Render(source)
+	}
+
+	return strings.Join(renderedDiagnostics, "\n")
+}
+
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == ERROR {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d Diagnostics) sorted() Diagnostics {
+	sortedDiagnostics := append(Diagnostics{}, d...)
+
+	sort.SliceStable(sortedDiagnostics, func(i, j int) bool {
+		if sortedDiagnostics[i].Line != sortedDiagnostics[j].Line {
+			return sortedDiagnostics[i].Line < sortedDiagnostics[j].Line
+		}
+
+		return sortedDiagnostics[i].Column < sortedDiagnostics[j].Column
+	})
+
+	return sortedDiagnostics
+}
+
+// bailout unwinds a statement that cannot be processed any further, its diagnostic is already recorded
+type bailout struct{}
+
+func recoverBailout(onBailout func()) {
+	if recovered := recover(); recovered != nil {
+		if _, ok := recovered.(bailout); !ok {
+			panic(recovered)
+		}
+
+		onBailout()
+	}
+}
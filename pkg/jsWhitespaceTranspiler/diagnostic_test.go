@@ -0,0 +1,30 @@
+package jsWhitespaceTranspiler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticRender(t *testing.T) {
+	input := "let number = 1;\nif (number) {\n\tconsole.log(number - \"text\");\n}\n"
+
+	expectedOutput := "test.js:3:14: error: type mismatch INT - STRING\n" +
+		"\tconsole.log(number - \"text\");\n" +
+		"\t            ^~~~~~~~~~~~~~~"
+
+	parsedAst, err := NewParser(NewNamedLexer(strings.NewReader(input), "test.js")).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	_, err = NewTranspiler().TranspileProgram(parsedAst)
+
+	errors, ok := err.(Diagnostics)
+	if !ok {
+		t.Fatalf("error type incorrect. expected=Diagnostics, got=%T", err)
+	}
+
+	if output := errors.Render(input); output != expectedOutput {
+		t.Errorf("rendered diagnostics incorrect. expected=\n%s\ngot=\n%s", expectedOutput, output)
+	}
+}
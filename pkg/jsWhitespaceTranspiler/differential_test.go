@@ -0,0 +1,145 @@
+package jsWhitespaceTranspiler
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/evaluator"
+	"github.com/pakut2/w-format/pkg/whitespace"
+)
+
+const examplesDirectory = "../../examples"
+
+func TestDifferential(t *testing.T) {
+	sourceFilePaths, err := filepath.Glob(filepath.Join(examplesDirectory, "corpus", "*.js"))
+	if err != nil {
+		t.Fatalf("cannot list corpus, error: %v", err)
+	}
+
+	sourceFilePaths = append(sourceFilePaths, filepath.Join(examplesDirectory, "source.js"))
+
+	for _, sourceFilePath := range sourceFilePaths {
+		t.Run(filepath.Base(sourceFilePath), func(t *testing.T) {
+			source, err := os.ReadFile(sourceFilePath)
+			if err != nil {
+				t.Fatalf("cannot read %q, error: %v", sourceFilePath, err)
+			}
+
+			// Programs reading the input get it from a file of the same name with the .stdin extension
+			input, err := os.ReadFile(strings.TrimSuffix(sourceFilePath, ".js") + ".stdin")
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatalf("cannot read input of %q, error: %v", sourceFilePath, err)
+			}
+
+			expectedOutput, err := evaluateReference(string(source), string(input))
+			if err != nil {
+				t.Fatalf("reference evaluation failed, error: %v", err)
+			}
+
+			for _, level := range []int{whitespace.NO_OPTIMIZATION, whitespace.LOCAL_OPTIMIZATION, whitespace.CONTROL_FLOW_OPTIMIZATION, whitespace.SIZE_OPTIMIZATION} {
+				for _, foldConstants := range []bool{false, true} {
+					whitespaceOutput, err := runOptimized(string(source), string(input), level, foldConstants)
+					if err != nil {
+						t.Fatalf("transpiled program failed at optimization level %d (constant folding: %t), error: %v", level, foldConstants, err)
+					}
+
+					if whitespaceOutput != expectedOutput {
+						t.Errorf("output mismatch at optimization level %d (constant folding: %t).\n%s", level, foldConstants, diffLines(expectedOutput, whitespaceOutput))
+					}
+				}
+			}
+		})
+	}
+}
+
+func parseSource(source string) (*ast.Program, error) {
+	return NewParser(NewLexer(strings.NewReader(source))).ParseProgram()
+}
+
+func evaluateReference(source, input string) (output string, err error) {
+	defer recoverPanic(&err)
+
+	program, err := parseSource(source)
+	if err != nil {
+		return "", err
+	}
+
+	var outputBuffer bytes.Buffer
+
+	if err = evaluator.New(strings.NewReader(input), &outputBuffer).Evaluate(program); err != nil {
+		return "", err
+	}
+
+	return outputBuffer.String(), nil
+}
+
+func runTranspiled(source, input string) (output string, err error) {
+	return runOptimized(source, input, whitespace.NO_OPTIMIZATION, false)
+}
+
+func runOptimized(source, input string, optimizationLevel int, foldConstants bool) (output string, err error) {
+	defer recoverPanic(&err)
+
+	parsedSource, err := parseSource(source)
+	if err != nil {
+		return "", err
+	}
+
+	if foldConstants {
+		parsedSource = NewConstantFolder().FoldProgram(parsedSource)
+	}
+
+	program, err := NewTranspiler().TranspileProgram(parsedSource)
+	if err != nil {
+		return "", err
+	}
+
+	var outputBuffer bytes.Buffer
+
+	vm, err := whitespace.NewVirtualMachine(whitespace.Optimize(program.Instructions(), optimizationLevel), strings.NewReader(input), &outputBuffer)
+	if err != nil {
+		return "", err
+	}
+
+	if err = vm.Run(); err != nil {
+		return "", err
+	}
+
+	return outputBuffer.String(), nil
+}
+
+func recoverPanic(err *error) {
+	if recovered := recover(); recovered != nil {
+		*err = fmt.Errorf("panic: %v", recovered)
+	}
+}
+
+func diffLines(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	var diff strings.Builder
+
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var expectedLine, actualLine string
+
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+
+		if expectedLine != actualLine {
+			fmt.Fprintf(&diff, "line %d: javascript=%q, whitespace=%q\n", i+1, expectedLine, actualLine)
+		}
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,502 @@
+package jsWhitespaceTranspiler
+
+import (
+	"strconv"
+
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/token"
+)
+
+// ConstantFolder evaluates expressions of literals at compile time, replaces constants declared with literals by their values
+// and drops branches that can never run. Expressions it cannot prove to behave the same as at runtime are left to the transpiler.
+type ConstantFolder struct {
+	scope *foldingScope
+}
+
+// foldingScope holds the literal values of constants, other declarations shadow outer constants of the same name
+type foldingScope struct {
+	outer     *foldingScope
+	constants map[string]ast.Expression
+}
+
+func newFoldingScope(outer *foldingScope) *foldingScope {
+	return &foldingScope{outer: outer, constants: make(map[string]ast.Expression)}
+}
+
+func (s *foldingScope) lookup(name string) (ast.Expression, bool) {
+	for scope := s; scope != nil; scope = scope.outer {
+		if value, ok := scope.constants[name]; ok {
+			return value, value != nil
+		}
+	}
+
+	return nil, false
+}
+
+func NewConstantFolder() *ConstantFolder {
+	return &ConstantFolder{scope: newFoldingScope(nil)}
+}
+
+func (f *ConstantFolder) FoldProgram(program *ast.Program) *ast.Program {
+	// Functions are hoisted and can run before any constant is declared, so they do not see constants of the program
+	for _, statement := range program.Statements {
+		if declaration, ok := statement.(*ast.FunctionDeclaration); ok {
+			f.scope.constants[declaration.Name.Value] = nil
+		}
+	}
+
+	program.Statements = f.foldStatements(program.Statements)
+
+	return program
+}
+
+func (f *ConstantFolder) foldStatements(statements []ast.Statement) []ast.Statement {
+	var folded []ast.Statement
+
+	for _, statement := range statements {
+		if foldedStatement := f.foldStatement(statement); foldedStatement != nil {
+			folded = append(folded, foldedStatement)
+		}
+	}
+
+	return folded
+}
+
+// foldStatement returns nil for statements that have no effect
+func (f *ConstantFolder) foldStatement(statement ast.Statement) ast.Statement {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		statement.Value = f.foldExpression(statement.Value)
+
+		if statement.IsConstant() && isLiteral(statement.Value) {
+			f.scope.constants[statement.Name.Value] = statement.Value
+		} else {
+			f.scope.constants[statement.Name.Value] = nil
+		}
+
+		return statement
+	case *ast.AssignmentStatement:
+		statement.Value = f.foldExpression(statement.Value)
+
+		return statement
+	case *ast.CompoundAssignmentStatement:
+		statement.Target = f.foldTarget(statement.Target)
+		statement.Value = f.foldExpression(statement.Value)
+
+		return statement
+	case *ast.IndexAssignmentStatement:
+		statement.Target = f.foldTarget(statement.Target).(*ast.IndexExpression)
+		statement.Value = f.foldExpression(statement.Value)
+
+		return statement
+	case *ast.IfStatement:
+		return f.foldIfStatement(statement)
+	case *ast.BlockStatement:
+		defer f.enterScope()()
+
+		statement.Statements = f.foldStatements(statement.Statements)
+
+		return statement
+	case *ast.ForStatement:
+		return f.foldForStatement(statement)
+	case *ast.WhileStatement:
+		statement.Condition = f.foldExpression(statement.Condition)
+
+		if truthy, ok := literalTruthiness(statement.Condition); ok && !truthy {
+			return nil
+		}
+
+		statement.Body = f.foldStatement(statement.Body).(*ast.BlockStatement)
+
+		return statement
+	case *ast.DoWhileStatement:
+		statement.Body = f.foldStatement(statement.Body).(*ast.BlockStatement)
+		statement.Condition = f.foldExpression(statement.Condition)
+
+		return statement
+	case *ast.LabeledStatement:
+		statement.Body = f.foldStatement(statement.Body)
+		if statement.Body == nil {
+			statement.Body = &ast.BlockStatement{Span: statement.Span, Token: statement.Token}
+		}
+
+		return statement
+	case *ast.FunctionDeclaration:
+		return f.foldFunctionDeclaration(statement)
+	case *ast.ReturnStatement:
+		if statement.Value != nil {
+			statement.Value = f.foldExpression(statement.Value)
+		}
+
+		return statement
+	case *ast.ExpressionStatement:
+		statement.Expression = f.foldExpression(statement.Expression)
+
+		if isLiteral(statement.Expression) {
+			return nil
+		}
+
+		return statement
+	default:
+		return statement
+	}
+}
+
+func (f *ConstantFolder) foldIfStatement(statement *ast.IfStatement) ast.Statement {
+	statement.Condition = f.foldExpression(statement.Condition)
+
+	truthy, ok := literalTruthiness(statement.Condition)
+	if !ok {
+		statement.Consequence = f.foldStatement(statement.Consequence).(*ast.BlockStatement)
+
+		if statement.Alternative != nil {
+			statement.Alternative = f.foldStatement(statement.Alternative).(*ast.BlockStatement)
+		}
+
+		return statement
+	}
+
+	// The branch stays a block, keeping its declarations in their own scope
+	if truthy {
+		return f.foldStatement(statement.Consequence)
+	}
+
+	if statement.Alternative != nil {
+		return f.foldStatement(statement.Alternative)
+	}
+
+	return nil
+}
+
+func (f *ConstantFolder) foldForStatement(statement *ast.ForStatement) ast.Statement {
+	defer f.enterScope()()
+
+	f.foldStatement(statement.Declaration)
+	statement.Boundary = f.foldExpression(statement.Boundary)
+
+	// A loop that never runs still declares its variable, evaluating the initial value
+	if truthy, ok := literalTruthiness(statement.Boundary); ok && !truthy {
+		return &ast.BlockStatement{Span: statement.Span, Token: statement.Token, Statements: []ast.Statement{statement.Declaration}}
+	}
+
+	if increment := f.foldStatement(statement.Increment); increment != nil {
+		statement.Increment = increment
+	}
+
+	statement.Body = f.foldStatement(statement.Body).(*ast.BlockStatement)
+
+	return statement
+}
+
+func (f *ConstantFolder) foldFunctionDeclaration(declaration *ast.FunctionDeclaration) ast.Statement {
+	previousScope := f.scope
+	f.scope = newFoldingScope(nil)
+
+	defer func() { f.scope = previousScope }()
+
+	for _, parameter := range declaration.Parameters {
+		f.scope.constants[parameter.Value] = nil
+	}
+
+	declaration.Body.Statements = f.foldStatements(declaration.Body.Statements)
+
+	return declaration
+}
+
+func (f *ConstantFolder) enterScope() func() {
+	previousScope := f.scope
+	f.scope = newFoldingScope(previousScope)
+
+	return func() { f.scope = previousScope }
+}
+
+// foldTarget folds the indexes of an assigned array element, the assigned variable itself is kept
+func (f *ConstantFolder) foldTarget(target ast.Expression) ast.Expression {
+	if index, ok := target.(*ast.IndexExpression); ok {
+		index.Left = f.foldTarget(index.Left)
+		index.Index = f.foldExpression(index.Index)
+	}
+
+	return target
+}
+
+func (f *ConstantFolder) foldExpression(expression ast.Expression) ast.Expression {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		if value, ok := f.scope.lookup(expression.Value); ok {
+			return withSpan(value, expression.Span)
+		}
+
+		return expression
+	case *ast.PrefixExpression:
+		if expression.Operator == ast.INCREMENT || expression.Operator == ast.DECREMENT {
+			expression.Right = f.foldTarget(expression.Right)
+
+			return expression
+		}
+
+		expression.Right = f.foldExpression(expression.Right)
+
+		return f.foldPrefixExpression(expression)
+	case *ast.SuffixExpression:
+		expression.Left = f.foldTarget(expression.Left)
+
+		return expression
+	case *ast.InfixExpression:
+		expression.Left = f.foldExpression(expression.Left)
+		expression.Right = f.foldExpression(expression.Right)
+
+		return f.foldInfixExpression(expression)
+	case *ast.ConditionalExpression:
+		expression.Condition = f.foldExpression(expression.Condition)
+		expression.Consequence = f.foldExpression(expression.Consequence)
+		expression.Alternative = f.foldExpression(expression.Alternative)
+
+		// Branches of different types are left for the transpiler to report
+		truthy, ok := literalTruthiness(expression.Condition)
+		if !ok || !isLiteral(expression.Consequence) || !sameLiteralType(expression.Consequence, expression.Alternative) {
+			return expression
+		}
+
+		if truthy {
+			return withSpan(expression.Consequence, expression.Span)
+		}
+
+		return withSpan(expression.Alternative, expression.Span)
+	case *ast.CallExpression:
+		expression.Function = f.foldExpression(expression.Function)
+
+		for i, argument := range expression.Arguments {
+			expression.Arguments[i] = f.foldExpression(argument)
+		}
+
+		if f.isConsoleLog(expression.Function) {
+			// Printed numbers and booleans look the same as strings, which are printed char by char
+			for i, argument := range expression.Arguments {
+				if printed, ok := printedLiteral(argument); ok {
+					expression.Arguments[i] = stringLiteral(printed, argument.NodeSpan())
+				}
+			}
+		}
+
+		return expression
+	case *ast.IndexExpression:
+		expression.Left = f.foldExpression(expression.Left)
+		expression.Index = f.foldExpression(expression.Index)
+
+		return expression
+	case *ast.MemberExpression:
+		expression.Object = f.foldExpression(expression.Object)
+
+		return expression
+	case *ast.ArrayLiteral:
+		for i, element := range expression.Elements {
+			expression.Elements[i] = f.foldExpression(element)
+		}
+
+		return expression
+	default:
+		return expression
+	}
+}
+
+func (f *ConstantFolder) isConsoleLog(function ast.Expression) bool {
+	member, ok := function.(*ast.MemberExpression)
+	if !ok || member.Property.Value != "log" {
+		return false
+	}
+
+	namespace, ok := member.Object.(*ast.Identifier)
+	if !ok || namespace.Value != "console" {
+		return false
+	}
+
+	for scope := f.scope; scope != nil; scope = scope.outer {
+		if _, declared := scope.constants[namespace.Value]; declared {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *ConstantFolder) foldPrefixExpression(expression *ast.PrefixExpression) ast.Expression {
+	switch expression.Operator {
+	case ast.SUBTRACTION:
+		if integer, ok := expression.Right.(*ast.IntegerLiteral); ok {
+			return integerLiteral(-integer.Value, expression.Span)
+		}
+	case ast.NEGATION:
+		if truthy, ok := literalTruthiness(expression.Right); ok {
+			return booleanLiteral(!truthy, expression.Span)
+		}
+	}
+
+	return expression
+}
+
+func (f *ConstantFolder) foldInfixExpression(expression *ast.InfixExpression) ast.Expression {
+	if !isLiteral(expression.Left) || !sameLiteralType(expression.Left, expression.Right) {
+		return expression
+	}
+
+	if expression.Operator == ast.AND || expression.Operator == ast.OR {
+		truthy, _ := literalTruthiness(expression.Left)
+
+		if truthy == (expression.Operator == ast.OR) {
+			return withSpan(expression.Left, expression.Span)
+		}
+
+		return withSpan(expression.Right, expression.Span)
+	}
+
+	switch left := expression.Left.(type) {
+	case *ast.IntegerLiteral:
+		return foldIntegerInfixExpression(expression, left.Value, expression.Right.(*ast.IntegerLiteral).Value)
+	case *ast.BooleanLiteral:
+		right := expression.Right.(*ast.BooleanLiteral).Value
+
+		switch expression.Operator {
+		case ast.EQUALS:
+			return booleanLiteral(left.Value == right, expression.Span)
+		case ast.NOT_EQUALS:
+			return booleanLiteral(left.Value != right, expression.Span)
+		}
+	case *ast.StringLiteral:
+		if expression.Operator == ast.ADDITION {
+			return stringLiteral(left.Value+expression.Right.(*ast.StringLiteral).Value, expression.Span)
+		}
+	}
+
+	return expression
+}
+
+// foldIntegerInfixExpression only folds divisions where rounding towards negative infinity, as Whitespace does,
+// gives the same result as Javascript
+func foldIntegerInfixExpression(expression *ast.InfixExpression, left, right int64) ast.Expression {
+	switch expression.Operator {
+	case ast.ADDITION:
+		return integerLiteral(left+right, expression.Span)
+	case ast.SUBTRACTION:
+		return integerLiteral(left-right, expression.Span)
+	case ast.MULTIPLICATION:
+		return integerLiteral(left*right, expression.Span)
+	case ast.DIVISION:
+		if right != 0 && left%right == 0 {
+			return integerLiteral(left/right, expression.Span)
+		}
+	case ast.MODULO:
+		if left >= 0 && right > 0 {
+			return integerLiteral(left%right, expression.Span)
+		}
+	case ast.EQUALS:
+		return booleanLiteral(left == right, expression.Span)
+	case ast.NOT_EQUALS:
+		return booleanLiteral(left != right, expression.Span)
+	case ast.LESS_THAN:
+		return booleanLiteral(left < right, expression.Span)
+	case ast.LESS_THAN_OR_EQUAL:
+		return booleanLiteral(left <= right, expression.Span)
+	case ast.GREATER_THAN:
+		return booleanLiteral(left > right, expression.Span)
+	case ast.GREATER_THAN_OR_EQUAL:
+		return booleanLiteral(left >= right, expression.Span)
+	}
+
+	return expression
+}
+
+func isLiteral(expression ast.Expression) bool {
+	switch expression.(type) {
+	case *ast.IntegerLiteral, *ast.BooleanLiteral, *ast.StringLiteral:
+		return true
+	default:
+		return false
+	}
+}
+
+func sameLiteralType(left, right ast.Expression) bool {
+	switch left.(type) {
+	case *ast.IntegerLiteral:
+		_, ok := right.(*ast.IntegerLiteral)
+
+		return ok
+	case *ast.BooleanLiteral:
+		_, ok := right.(*ast.BooleanLiteral)
+
+		return ok
+	case *ast.StringLiteral:
+		_, ok := right.(*ast.StringLiteral)
+
+		return ok
+	default:
+		return false
+	}
+}
+
+func literalTruthiness(expression ast.Expression) (bool, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return expression.Value != 0, true
+	case *ast.BooleanLiteral:
+		return expression.Value, true
+	case *ast.StringLiteral:
+		return expression.Value != "", true
+	default:
+		return false, false
+	}
+}
+
+func printedLiteral(expression ast.Expression) (string, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return strconv.FormatInt(expression.Value, 10), true
+	case *ast.BooleanLiteral:
+		return strconv.FormatBool(expression.Value), true
+	default:
+		return "", false
+	}
+}
+
+// withSpan copies a literal to the place of the expression it replaces, so diagnostics point at the source
+func withSpan(literal ast.Expression, span ast.Span) ast.Expression {
+	switch literal := literal.(type) {
+	case *ast.IntegerLiteral:
+		return integerLiteral(literal.Value, span)
+	case *ast.BooleanLiteral:
+		return booleanLiteral(literal.Value, span)
+	case *ast.StringLiteral:
+		return stringLiteral(literal.Value, span)
+	default:
+		return literal
+	}
+}
+
+func integerLiteral(value int64, span ast.Span) *ast.IntegerLiteral {
+	return &ast.IntegerLiteral{
+		Span:  span,
+		Token: token.Token{Type: token.INT, Literal: strconv.FormatInt(value, 10), Start: span.Start, End: span.End},
+		Value: value,
+	}
+}
+
+func booleanLiteral(value bool, span ast.Span) *ast.BooleanLiteral {
+	tokenType := token.TokenType(token.FALSE)
+	if value {
+		tokenType = token.TRUE
+	}
+
+	return &ast.BooleanLiteral{
+		Span:  span,
+		Token: token.Token{Type: tokenType, Literal: strconv.FormatBool(value), Start: span.Start, End: span.End},
+		Value: value,
+	}
+}
+
+func stringLiteral(value string, span ast.Span) *ast.StringLiteral {
+	return &ast.StringLiteral{
+		Span:  span,
+		Token: token.Token{Type: token.STRING, Literal: value, Start: span.Start, End: span.End},
+		Value: value,
+	}
+}
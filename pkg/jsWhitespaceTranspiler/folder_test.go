@@ -0,0 +1,94 @@
+package jsWhitespaceTranspiler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstantFolder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`console.log(2 + 2 * 2);`, `console.log("6");`},
+		{`console.log(-(3 - 5), 1 < 2, !0, "a" + "b" + "c");`, `console.log("2", "true", "true", "abc");`},
+		{`if (false) { console.log("never"); }`, ``},
+		{`if (1 > 2) { console.log("no"); } else { console.log("yes"); }`, `{ console.log("yes"); }`},
+		{`while (0) { console.log("never"); }`, ``},
+		{`for (let i = 0; false; i++) { console.log(i); }`, `{ let i = 0; }`},
+		{`const a = 3; const b = a * a; let c = b - a; console.log(c, b);`, `const a = 3; const b = 9; let c = 6; console.log(c, "9");`},
+		{`const a = 1; { const a = 2; console.log(a); } console.log(a);`, `const a = 1; { const a = 2; console.log("2"); } console.log("1");`},
+		{`const a = 1; { let a = 2; a++; console.log(a); }`, `const a = 1; { let a = 2; a++; console.log(a); }`},
+		{`const a = 1; function f(a) { return a; } console.log(f(a));`, `const a = 1; function f(a) { return a; } console.log(f(1));`},
+		{`const n = 2; function f() { return n; } console.log(f());`, `const n = 2; function f() { return n; } console.log(f());`},
+		{`const i = 1; let x = [0, 0]; x[i] = i + 1; x[i] += i; console.log(x[i]);`, `const i = 1; let x = [0, 0]; x[1] = 2; x[1] += 1; console.log(x[1]);`},
+		{`let a = 7; console.log(a / 2, 7 / 2, a % 2, 1 / 0);`, `let a = 7; console.log(a / 2, 7 / 2, a % 2, 1 / 0);`},
+		{`console.log(true ? 1 : 2, false ? "a" : "b", 0 || 4, 3 && 0);`, `console.log("1", "b", "4", "0");`},
+		{`let a = 1; console.log(true ? a : 2, 1 + 2 > a);`, `let a = 1; console.log(true ? a : 2, 3 > a);`},
+		{`2 + 3; console.log(1);`, `console.log("1");`},
+	}
+
+	for _, test := range tests {
+		parsedSource, err := parseSource(test.input)
+		if err != nil {
+			t.Fatalf("cannot parse %q, error: %v", test.input, err)
+		}
+
+		program, err := NewTranspiler().TranspileProgram(NewConstantFolder().FoldProgram(parsedSource))
+		if err != nil {
+			t.Fatalf("cannot transpile folded %q, error: %v", test.input, err)
+		}
+
+		expectedSource, err := parseSource(test.expected)
+		if err != nil {
+			t.Fatalf("cannot parse %q, error: %v", test.expected, err)
+		}
+
+		expectedProgram, err := NewTranspiler().TranspileProgram(expectedSource)
+		if err != nil {
+			t.Fatalf("cannot transpile %q, error: %v", test.expected, err)
+		}
+
+		instructions, expectedInstructions := program.Instructions(), expectedProgram.Instructions()
+
+		if len(instructions) != len(expectedInstructions) {
+			t.Errorf("folded %q incorrect. expected instructions of %q, got=%v", test.input, test.expected, instructions)
+
+			continue
+		}
+
+		for i, expectedInstruction := range expectedInstructions {
+			if instructions[i] != expectedInstruction {
+				t.Errorf("instruction (#%d) of folded %q incorrect. expected=%q, got=%q", i, test.input, expectedInstruction, instructions[i])
+			}
+		}
+	}
+}
+
+func TestConstantFolderErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`console.log(true && "yes");`, "1:13: error: type mismatch BOOLEAN && STRING"},
+		{`const a = "x"; console.log(a === "x");`, "1:28: error: unknown operator STRING === STRING"},
+		{`const a = 1; a++;`, "1:14: error: assignment to constant variable a"},
+		{`const a = 1; let b = [true]; b[0] = a + 1;`, "1:37: error: array element type mismatch BOOLEAN, INT"},
+	}
+
+	for _, test := range tests {
+		parsedSource, err := parseSource(test.input)
+		if err != nil {
+			t.Fatalf("cannot parse %q, error: %v", test.input, err)
+		}
+
+		_, err = NewTranspiler().TranspileProgram(NewConstantFolder().FoldProgram(parsedSource))
+		if err == nil {
+			t.Fatalf("expected error for %q", test.input)
+		}
+
+		if !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("error of folded %q incorrect. expected=%q, got=%q", test.input, test.expectedError, err.Error())
+		}
+	}
+}
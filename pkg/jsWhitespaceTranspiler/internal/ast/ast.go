@@ -21,7 +21,18 @@ const (
 	OR                    = token.OR
 )
 
-type Node interface{}
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
+
+func TokenSpan(t token.Token) Span {
+	return Span{Start: t.Start, End: t.End}
+}
+
+type Node interface {
+	NodeSpan() Span
+}
 
 type Statement interface {
 	Node
@@ -34,26 +45,60 @@ type Expression interface {
 }
 
 type Program struct {
+	Span       Span
+	FileName   string
 	Statements []Statement
 }
 
+func (p *Program) NodeSpan() Span { return p.Span }
+
 type LetStatement struct {
+	Span  Span
 	Token token.Token
 	Name  *Identifier
 	Value Expression
 }
 
 func (ls *LetStatement) statementNode() {}
+func (ls *LetStatement) NodeSpan() Span { return ls.Span }
+
+// IsConstant reports whether the declaration uses const, which forbids reassignment
+func (ls *LetStatement) IsConstant() bool { return ls.Token.Type == token.CONST }
 
 type AssignmentStatement struct {
+	Span  Span
 	Token token.Token
 	Name  *Identifier
 	Value Expression
 }
 
 func (a *AssignmentStatement) statementNode() {}
+func (a *AssignmentStatement) NodeSpan() Span { return a.Span }
+
+// CompoundAssignmentStatement updates a variable or an array element with an arithmetic operator, e.g. a += 1
+type CompoundAssignmentStatement struct {
+	Span     Span
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (c *CompoundAssignmentStatement) statementNode() {}
+func (c *CompoundAssignmentStatement) NodeSpan() Span { return c.Span }
+
+type IndexAssignmentStatement struct {
+	Span   Span
+	Token  token.Token
+	Target *IndexExpression
+	Value  Expression
+}
+
+func (i *IndexAssignmentStatement) statementNode() {}
+func (i *IndexAssignmentStatement) NodeSpan() Span { return i.Span }
 
 type IfStatement struct {
+	Span        Span
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
@@ -61,51 +106,117 @@ type IfStatement struct {
 }
 
 func (i *IfStatement) statementNode() {}
+func (i *IfStatement) NodeSpan() Span { return i.Span }
 
 type BlockStatement struct {
+	Span       Span
 	Token      token.Token
 	Statements []Statement
 }
 
 func (b *BlockStatement) statementNode() {}
+func (b *BlockStatement) NodeSpan() Span { return b.Span }
 
 type ForStatement struct {
+	Span        Span
 	Token       token.Token
 	Declaration *LetStatement
 	Boundary    Expression
-	Increment   Expression
+	Increment   Statement
 	Body        *BlockStatement
 }
 
 func (f *ForStatement) statementNode() {}
+func (f *ForStatement) NodeSpan() Span { return f.Span }
+
+type WhileStatement struct {
+	Span      Span
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (w *WhileStatement) statementNode() {}
+func (w *WhileStatement) NodeSpan() Span { return w.Span }
+
+type DoWhileStatement struct {
+	Span      Span
+	Token     token.Token
+	Body      *BlockStatement
+	Condition Expression
+}
+
+func (d *DoWhileStatement) statementNode() {}
+func (d *DoWhileStatement) NodeSpan() Span { return d.Span }
+
+type LabeledStatement struct {
+	Span  Span
+	Token token.Token
+	Label *Identifier
+	Body  Statement
+}
+
+func (l *LabeledStatement) statementNode() {}
+func (l *LabeledStatement) NodeSpan() Span { return l.Span }
 
 type BreakStatement struct {
+	Span  Span
 	Token token.Token
+	Label *Identifier
 }
 
 func (b *BreakStatement) statementNode() {}
+func (b *BreakStatement) NodeSpan() Span { return b.Span }
 
 type ContinueStatement struct {
+	Span  Span
 	Token token.Token
+	Label *Identifier
 }
 
 func (c *ContinueStatement) statementNode() {}
+func (c *ContinueStatement) NodeSpan() Span { return c.Span }
+
+type FunctionDeclaration struct {
+	Span       Span
+	Token      token.Token
+	Name       *Identifier
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (f *FunctionDeclaration) statementNode() {}
+func (f *FunctionDeclaration) NodeSpan() Span { return f.Span }
+
+type ReturnStatement struct {
+	Span  Span
+	Token token.Token
+	Value Expression
+}
+
+func (r *ReturnStatement) statementNode() {}
+func (r *ReturnStatement) NodeSpan() Span { return r.Span }
 
 type ExpressionStatement struct {
+	Span       Span
 	Expression Expression
 }
 
 func (es *ExpressionStatement) statementNode() {}
+func (es *ExpressionStatement) NodeSpan() Span { return es.Span }
 
 type PrefixExpression struct {
+	Span     Span
 	Token    token.Token
 	Operator string
 	Right    Expression
 }
 
 func (p *PrefixExpression) expressionNode() {}
+func (p *PrefixExpression) NodeSpan() Span  { return p.Span }
 
 type InfixExpression struct {
+	Span     Span
 	Token    token.Token
 	Left     Expression
 	Operator string
@@ -113,39 +224,100 @@ type InfixExpression struct {
 }
 
 func (i *InfixExpression) expressionNode() {}
+func (i *InfixExpression) NodeSpan() Span  { return i.Span }
+
+type ConditionalExpression struct {
+	Span        Span
+	Token       token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (c *ConditionalExpression) expressionNode() {}
+func (c *ConditionalExpression) NodeSpan() Span  { return c.Span }
 
 type SuffixExpression struct {
+	Span     Span
 	Token    token.Token
 	Left     Expression
 	Operator string
 }
 
 func (s *SuffixExpression) expressionNode() {}
+func (s *SuffixExpression) NodeSpan() Span  { return s.Span }
 
 type Identifier struct {
+	Span  Span
 	Token token.Token
 	Value string
 }
 
 func (i *Identifier) expressionNode() {}
+func (i *Identifier) NodeSpan() Span  { return i.Span }
 
 type CallExpression struct {
+	Span      Span
+	Token     token.Token
 	Function  Expression
 	Arguments []Expression
 }
 
 func (ce *CallExpression) expressionNode() {}
+func (ce *CallExpression) NodeSpan() Span  { return ce.Span }
+
+type IndexExpression struct {
+	Span  Span
+	Token token.Token
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode() {}
+func (ie *IndexExpression) NodeSpan() Span  { return ie.Span }
+
+type MemberExpression struct {
+	Span     Span
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode() {}
+func (me *MemberExpression) NodeSpan() Span  { return me.Span }
+
+type ArrayLiteral struct {
+	Span     Span
+	Token    token.Token
+	Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode() {}
+func (al *ArrayLiteral) NodeSpan() Span  { return al.Span }
 
 type StringLiteral struct {
+	Span  Span
 	Token token.Token
 	Value string
 }
 
 func (s *StringLiteral) expressionNode() {}
+func (s *StringLiteral) NodeSpan() Span  { return s.Span }
 
 type IntegerLiteral struct {
+	Span  Span
 	Token token.Token
 	Value int64
 }
 
 func (i *IntegerLiteral) expressionNode() {}
+func (i *IntegerLiteral) NodeSpan() Span  { return i.Span }
+
+type BooleanLiteral struct {
+	Span  Span
+	Token token.Token
+	Value bool
+}
+
+func (b *BooleanLiteral) expressionNode() {}
+func (b *BooleanLiteral) NodeSpan() Span  { return b.Span }
@@ -0,0 +1,63 @@
+package evaluator
+
+import "fmt"
+
+type environment struct {
+	store     map[string]any
+	constants map[string]bool
+	outer     *environment
+}
+
+func newEnvironment(outer *environment) *environment {
+	return &environment{store: make(map[string]any), constants: make(map[string]bool), outer: outer}
+}
+
+func (e *environment) get(name string) (any, bool) {
+	if value, ok := e.store[name]; ok {
+		return value, true
+	}
+
+	if e.outer != nil {
+		return e.outer.get(name)
+	}
+
+	return nil, false
+}
+
+func (e *environment) declare(name string, value any) error {
+	if _, ok := e.store[name]; ok {
+		return fmt.Errorf("identifier %s has already been declared", name)
+	}
+
+	e.store[name] = value
+
+	return nil
+}
+
+func (e *environment) declareConstant(name string, value any) error {
+	if err := e.declare(name, value); err != nil {
+		return err
+	}
+
+	e.constants[name] = true
+
+	return nil
+}
+
+func (e *environment) assign(name string, value any) error {
+	if _, ok := e.store[name]; ok {
+		if e.constants[name] {
+			return fmt.Errorf("assignment to constant variable %s", name)
+		}
+
+		e.store[name] = value
+
+		return nil
+	}
+
+	if e.outer != nil {
+		return e.outer.assign(name, value)
+	}
+
+	return fmt.Errorf("%s is not defined", name)
+}
@@ -0,0 +1,815 @@
+package evaluator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+)
+
+type Evaluator struct {
+	input  *bufio.Reader
+	output *bufio.Writer
+
+	environment      *environment
+	builtInFunctions map[string]builtInFunction
+	returnValue      any
+	flowLabel        string
+}
+
+type undefined struct{}
+
+type builtInFunction func(args ...any) (any, error)
+
+// Arrays are shared by reference, the same as in Javascript
+type array struct {
+	elements []any
+}
+
+type declaredFunction struct {
+	declaration *ast.FunctionDeclaration
+	environment *environment
+}
+
+type controlFlow int
+
+const (
+	normalFlow controlFlow = iota
+	breakFlow
+	continueFlow
+	returnFlow
+)
+
+func New(input io.Reader, output io.Writer) *Evaluator {
+	e := &Evaluator{
+		input:       bufio.NewReader(input),
+		output:      bufio.NewWriter(output),
+		environment: newEnvironment(nil),
+	}
+
+	e.builtInFunctions = map[string]builtInFunction{
+		"console.log": e.consoleLog,
+		"readline":    e.readline,
+		"readInt":     e.readInt,
+	}
+
+	return e
+}
+
+func (e *Evaluator) Evaluate(program *ast.Program) error {
+	err := e.evaluateStatements(program.Statements)
+
+	if flushErr := e.output.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+
+	return err
+}
+
+func (e *Evaluator) consoleLog(args ...any) (any, error) {
+	formattedArgs := make([]string, len(args))
+
+	for i, arg := range args {
+		formattedArgs[i] = toString(arg)
+	}
+
+	if _, err := fmt.Fprintln(e.output, strings.Join(formattedArgs, " ")); err != nil {
+		return nil, err
+	}
+
+	return undefined{}, nil
+}
+
+func (e *Evaluator) readInputLine() (string, error) {
+	if err := e.output.Flush(); err != nil {
+		return "", err
+	}
+
+	line, err := e.input.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+func (e *Evaluator) readline(args ...any) (any, error) {
+	return e.readInputLine()
+}
+
+func (e *Evaluator) readInt(args ...any) (any, error) {
+	line, err := e.readInputLine()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q as number", strings.TrimSpace(line))
+	}
+
+	return float64(value), nil
+}
+
+func (e *Evaluator) evaluateStatements(statements []ast.Statement) error {
+	flow, err := e.evaluateBlock(statements)
+	if err != nil {
+		return err
+	}
+
+	if flow != normalFlow {
+		return fmt.Errorf("illegal break or continue statement")
+	}
+
+	return nil
+}
+
+func (e *Evaluator) evaluateBlock(statements []ast.Statement) (controlFlow, error) {
+	for _, statement := range statements {
+		if declaration, ok := statement.(*ast.FunctionDeclaration); ok {
+			err := e.environment.declare(declaration.Name.Value, &declaredFunction{declaration: declaration, environment: e.environment})
+			if err != nil {
+				return normalFlow, err
+			}
+		}
+	}
+
+	for _, statement := range statements {
+		flow, err := e.evaluateStatement(statement)
+		if err != nil || flow != normalFlow {
+			return flow, err
+		}
+	}
+
+	return normalFlow, nil
+}
+
+func (e *Evaluator) evaluateStatement(statement ast.Statement) (controlFlow, error) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		value, err := e.evaluateExpression(statement.Value)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		if statement.IsConstant() {
+			return normalFlow, e.environment.declareConstant(statement.Name.Value, value)
+		}
+
+		return normalFlow, e.environment.declare(statement.Name.Value, value)
+	case *ast.AssignmentStatement:
+		value, err := e.evaluateExpression(statement.Value)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		return normalFlow, e.environment.assign(statement.Name.Value, value)
+	case *ast.IndexAssignmentStatement:
+		return normalFlow, e.evaluateIndexAssignmentStatement(statement)
+	case *ast.CompoundAssignmentStatement:
+		return normalFlow, e.evaluateCompoundAssignmentStatement(statement)
+	case *ast.IfStatement:
+		condition, err := e.evaluateExpression(statement.Condition)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		if toBoolean(condition) {
+			return e.evaluateScopedBlock(statement.Consequence)
+		}
+
+		if statement.Alternative != nil {
+			return e.evaluateScopedBlock(statement.Alternative)
+		}
+
+		return normalFlow, nil
+	case *ast.BlockStatement:
+		return e.evaluateScopedBlock(statement)
+	case *ast.ForStatement:
+		return e.evaluateForStatement(statement, nil)
+	case *ast.WhileStatement:
+		return e.evaluateLoop(statement.Condition, statement.Body, true, nil)
+	case *ast.DoWhileStatement:
+		return e.evaluateLoop(statement.Condition, statement.Body, false, nil)
+	case *ast.LabeledStatement:
+		return e.evaluateLabeledStatement(statement, nil)
+	case *ast.BreakStatement:
+		e.flowLabel = ""
+
+		if statement.Label != nil {
+			e.flowLabel = statement.Label.Value
+		}
+
+		return breakFlow, nil
+	case *ast.ContinueStatement:
+		e.flowLabel = ""
+
+		if statement.Label != nil {
+			e.flowLabel = statement.Label.Value
+		}
+
+		return continueFlow, nil
+	case *ast.FunctionDeclaration:
+		return normalFlow, nil
+	case *ast.ReturnStatement:
+		e.returnValue = undefined{}
+
+		if statement.Value != nil {
+			value, err := e.evaluateExpression(statement.Value)
+			if err != nil {
+				return normalFlow, err
+			}
+
+			e.returnValue = value
+		}
+
+		return returnFlow, nil
+	case *ast.ExpressionStatement:
+		_, err := e.evaluateExpression(statement.Expression)
+
+		return normalFlow, err
+	default:
+		return normalFlow, fmt.Errorf("unsupported statement %T", statement)
+	}
+}
+
+func (e *Evaluator) evaluateScopedBlock(block *ast.BlockStatement) (controlFlow, error) {
+	previousEnvironment := e.environment
+	e.environment = newEnvironment(previousEnvironment)
+
+	defer func() { e.environment = previousEnvironment }()
+
+	return e.evaluateBlock(block.Statements)
+}
+
+func (e *Evaluator) evaluateLabeledStatement(statement *ast.LabeledStatement, labels []string) (controlFlow, error) {
+	labels = append(labels, statement.Label.Value)
+
+	switch body := statement.Body.(type) {
+	case *ast.LabeledStatement:
+		return e.evaluateLabeledStatement(body, labels)
+	case *ast.ForStatement:
+		return e.evaluateForStatement(body, labels)
+	case *ast.WhileStatement:
+		return e.evaluateLoop(body.Condition, body.Body, true, labels)
+	case *ast.DoWhileStatement:
+		return e.evaluateLoop(body.Condition, body.Body, false, labels)
+	}
+
+	flow, err := e.evaluateStatement(statement.Body)
+	if flow == breakFlow && slices.Contains(labels, e.flowLabel) {
+		return normalFlow, err
+	}
+
+	return flow, err
+}
+
+// loopExit decides whether a loop stops after its body finished with the given flow, and with which flow it does
+func (e *Evaluator) loopExit(flow controlFlow, labels []string) (bool, controlFlow) {
+	targetsLoop := e.flowLabel == "" || slices.Contains(labels, e.flowLabel)
+
+	switch {
+	case flow == breakFlow && targetsLoop:
+		return true, normalFlow
+	case flow == continueFlow && targetsLoop, flow == normalFlow:
+		return false, normalFlow
+	default:
+		return true, flow
+	}
+}
+
+func (e *Evaluator) evaluateForStatement(statement *ast.ForStatement, labels []string) (controlFlow, error) {
+	previousEnvironment := e.environment
+	e.environment = newEnvironment(previousEnvironment)
+
+	defer func() { e.environment = previousEnvironment }()
+
+	if _, err := e.evaluateStatement(statement.Declaration); err != nil {
+		return normalFlow, err
+	}
+
+	for {
+		condition, err := e.evaluateExpression(statement.Boundary)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		if !toBoolean(condition) {
+			return normalFlow, nil
+		}
+
+		flow, err := e.evaluateScopedBlock(statement.Body)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		if exit, flow := e.loopExit(flow, labels); exit {
+			return flow, nil
+		}
+
+		if _, err = e.evaluateStatement(statement.Increment); err != nil {
+			return normalFlow, err
+		}
+	}
+}
+
+func (e *Evaluator) evaluateLoop(condition ast.Expression, body *ast.BlockStatement, checkFirst bool, labels []string) (controlFlow, error) {
+	for {
+		if checkFirst {
+			conditionValue, err := e.evaluateExpression(condition)
+			if err != nil {
+				return normalFlow, err
+			}
+
+			if !toBoolean(conditionValue) {
+				return normalFlow, nil
+			}
+		}
+
+		checkFirst = true
+
+		flow, err := e.evaluateScopedBlock(body)
+		if err != nil {
+			return normalFlow, err
+		}
+
+		if exit, flow := e.loopExit(flow, labels); exit {
+			return flow, nil
+		}
+	}
+}
+
+func (e *Evaluator) evaluateExpression(expression ast.Expression) (any, error) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return float64(expression.Value), nil
+	case *ast.BooleanLiteral:
+		return expression.Value, nil
+	case *ast.StringLiteral:
+		return expression.Value, nil
+	case *ast.Identifier:
+		if value, ok := e.environment.get(expression.Value); ok {
+			return value, nil
+		}
+
+		if function, ok := e.builtInFunctions[expression.Value]; ok {
+			return function, nil
+		}
+
+		return nil, fmt.Errorf("%s is not defined", expression.Value)
+	case *ast.ArrayLiteral:
+		elements := make([]any, len(expression.Elements))
+
+		for i, element := range expression.Elements {
+			value, err := e.evaluateExpression(element)
+			if err != nil {
+				return nil, err
+			}
+
+			elements[i] = value
+		}
+
+		return &array{elements: elements}, nil
+	case *ast.IndexExpression:
+		target, index, err := e.evaluateIndexTarget(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		return target.elements[index], nil
+	case *ast.MemberExpression:
+		return e.evaluateMemberExpression(expression)
+	case *ast.CallExpression:
+		return e.evaluateCallExpression(expression)
+	case *ast.PrefixExpression:
+		if expression.Operator == ast.INCREMENT || expression.Operator == ast.DECREMENT {
+			return e.evaluateUpdateExpression(expression.Right, expression.Operator, true)
+		}
+
+		right, err := e.evaluateExpression(expression.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		return evaluatePrefixExpression(expression.Operator, right)
+	case *ast.InfixExpression:
+		return e.evaluateInfixExpression(expression)
+	case *ast.SuffixExpression:
+		return e.evaluateUpdateExpression(expression.Left, expression.Operator, false)
+	case *ast.ConditionalExpression:
+		condition, err := e.evaluateExpression(expression.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if toBoolean(condition) {
+			return e.evaluateExpression(expression.Consequence)
+		}
+
+		return e.evaluateExpression(expression.Alternative)
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", expression)
+	}
+}
+
+func (e *Evaluator) evaluateIndexTarget(expression *ast.IndexExpression) (*array, int, error) {
+	left, err := e.evaluateExpression(expression.Left)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	target, ok := left.(*array)
+	if !ok {
+		return nil, 0, fmt.Errorf("cannot index %s", toString(left))
+	}
+
+	index, err := e.evaluateExpression(expression.Index)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	position := toNumber(index)
+	if position < 0 || position >= float64(len(target.elements)) || position != math.Trunc(position) {
+		return nil, 0, fmt.Errorf("array index %s out of bounds", toString(index))
+	}
+
+	return target, int(position), nil
+}
+
+func (e *Evaluator) evaluateIndexAssignmentStatement(statement *ast.IndexAssignmentStatement) error {
+	target, index, err := e.evaluateIndexTarget(statement.Target)
+	if err != nil {
+		return err
+	}
+
+	value, err := e.evaluateExpression(statement.Value)
+	if err != nil {
+		return err
+	}
+
+	target.elements[index] = value
+
+	return nil
+}
+
+func (e *Evaluator) evaluateMemberExpression(expression *ast.MemberExpression) (any, error) {
+	if namespace, ok := expression.Object.(*ast.Identifier); ok {
+		if _, isVariable := e.environment.get(namespace.Value); !isVariable {
+			if function, ok := e.builtInFunctions[namespace.Value+"."+expression.Property.Value]; ok {
+				return function, nil
+			}
+		}
+	}
+
+	value, err := e.evaluateExpression(expression.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := value.(*array)
+	if !ok {
+		return nil, fmt.Errorf("property %s does not exist on %s", expression.Property.Value, toString(value))
+	}
+
+	switch expression.Property.Value {
+	case "length":
+		return float64(len(target.elements)), nil
+	case "push":
+		return builtInFunction(func(args ...any) (any, error) {
+			target.elements = append(target.elements, args...)
+
+			return float64(len(target.elements)), nil
+		}), nil
+	default:
+		return nil, fmt.Errorf("property %s does not exist on array", expression.Property.Value)
+	}
+}
+
+func (e *Evaluator) evaluateCallExpression(expression *ast.CallExpression) (any, error) {
+	function, err := e.evaluateExpression(expression.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []any
+
+	for _, argument := range expression.Arguments {
+		value, err := e.evaluateExpression(argument)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	switch function := function.(type) {
+	case builtInFunction:
+		return function(args...)
+	case *declaredFunction:
+		return e.callFunction(function, args)
+	default:
+		return nil, fmt.Errorf("%s is not a function", toString(function))
+	}
+}
+
+func (e *Evaluator) callFunction(function *declaredFunction, args []any) (any, error) {
+	previousEnvironment := e.environment
+	e.environment = newEnvironment(function.environment)
+
+	defer func() { e.environment = previousEnvironment }()
+
+	for i, parameter := range function.declaration.Parameters {
+		var value any = undefined{}
+
+		if i < len(args) {
+			value = args[i]
+		}
+
+		if err := e.environment.declare(parameter.Value, value); err != nil {
+			return nil, err
+		}
+	}
+
+	flow, err := e.evaluateBlock(function.declaration.Body.Statements)
+	if err != nil {
+		return nil, err
+	}
+
+	switch flow {
+	case returnFlow:
+		return e.returnValue, nil
+	case normalFlow:
+		return undefined{}, nil
+	default:
+		return nil, fmt.Errorf("illegal break or continue statement")
+	}
+}
+
+func evaluatePrefixExpression(operator string, right any) (any, error) {
+	switch operator {
+	case ast.SUBTRACTION:
+		return -toNumber(right), nil
+	case ast.NEGATION:
+		return !toBoolean(right), nil
+	default:
+		return nil, fmt.Errorf("unknown operator %s", operator)
+	}
+}
+
+func (e *Evaluator) evaluateInfixExpression(expression *ast.InfixExpression) (any, error) {
+	left, err := e.evaluateExpression(expression.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expression.Operator {
+	case ast.AND:
+		if !toBoolean(left) {
+			return left, nil
+		}
+
+		return e.evaluateExpression(expression.Right)
+	case ast.OR:
+		if toBoolean(left) {
+			return left, nil
+		}
+
+		return e.evaluateExpression(expression.Right)
+	}
+
+	right, err := e.evaluateExpression(expression.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyOperator(expression.Operator, left, right)
+}
+
+func applyOperator(operator string, left, right any) (any, error) {
+	switch operator {
+	case ast.ADDITION:
+		leftString, leftIsString := left.(string)
+		rightString, rightIsString := right.(string)
+
+		if leftIsString || rightIsString {
+			if !leftIsString {
+				leftString = toString(left)
+			}
+
+			if !rightIsString {
+				rightString = toString(right)
+			}
+
+			return leftString + rightString, nil
+		}
+
+		return toNumber(left) + toNumber(right), nil
+	case ast.SUBTRACTION:
+		return toNumber(left) - toNumber(right), nil
+	case ast.MULTIPLICATION:
+		return toNumber(left) * toNumber(right), nil
+	case ast.DIVISION:
+		return toNumber(left) / toNumber(right), nil
+	case ast.MODULO:
+		return math.Mod(toNumber(left), toNumber(right)), nil
+	case ast.EQUALS:
+		return left == right, nil
+	case ast.NOT_EQUALS:
+		return left != right, nil
+	case ast.LESS_THAN, ast.LESS_THAN_OR_EQUAL, ast.GREATER_THAN, ast.GREATER_THAN_OR_EQUAL:
+		return compare(operator, left, right), nil
+	default:
+		return nil, fmt.Errorf("unknown operator %s", operator)
+	}
+}
+
+// evaluateReference returns the current value of a variable or an array element, and a function assigning a new one
+func (e *Evaluator) evaluateReference(target ast.Expression) (any, func(value any) error, error) {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		value, ok := e.environment.get(target.Value)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s is not defined", target.Value)
+		}
+
+		return value, func(value any) error { return e.environment.assign(target.Value, value) }, nil
+	case *ast.IndexExpression:
+		array, index, err := e.evaluateIndexTarget(target)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return array.elements[index], func(value any) error {
+			array.elements[index] = value
+
+			return nil
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid assignment target")
+	}
+}
+
+func (e *Evaluator) evaluateCompoundAssignmentStatement(statement *ast.CompoundAssignmentStatement) error {
+	currentValue, assign, err := e.evaluateReference(statement.Target)
+	if err != nil {
+		return err
+	}
+
+	value, err := e.evaluateExpression(statement.Value)
+	if err != nil {
+		return err
+	}
+
+	result, err := applyOperator(statement.Operator, currentValue, value)
+	if err != nil {
+		return err
+	}
+
+	return assign(result)
+}
+
+func (e *Evaluator) evaluateUpdateExpression(operand ast.Expression, operator string, prefix bool) (any, error) {
+	currentValue, assign, err := e.evaluateReference(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	previousValue := toNumber(currentValue)
+
+	var updatedValue float64
+
+	switch operator {
+	case ast.INCREMENT:
+		updatedValue = previousValue + 1
+	case ast.DECREMENT:
+		updatedValue = previousValue - 1
+	default:
+		return nil, fmt.Errorf("unknown operator %s", operator)
+	}
+
+	if err = assign(updatedValue); err != nil {
+		return nil, err
+	}
+
+	if prefix {
+		return updatedValue, nil
+	}
+
+	return previousValue, nil
+}
+
+func compare(operator string, left, right any) bool {
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+
+	if leftIsString && rightIsString {
+		switch operator {
+		case ast.LESS_THAN:
+			return leftString < rightString
+		case ast.LESS_THAN_OR_EQUAL:
+			return leftString <= rightString
+		case ast.GREATER_THAN:
+			return leftString > rightString
+		default:
+			return leftString >= rightString
+		}
+	}
+
+	leftNumber := toNumber(left)
+	rightNumber := toNumber(right)
+
+	switch operator {
+	case ast.LESS_THAN:
+		return leftNumber < rightNumber
+	case ast.LESS_THAN_OR_EQUAL:
+		return leftNumber <= rightNumber
+	case ast.GREATER_THAN:
+		return leftNumber > rightNumber
+	default:
+		return leftNumber >= rightNumber
+	}
+}
+
+func toNumber(value any) float64 {
+	switch value := value.(type) {
+	case float64:
+		return value
+	case bool:
+		if value {
+			return 1
+		}
+
+		return 0
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			if strings.TrimSpace(value) == "" {
+				return 0
+			}
+
+			return math.NaN()
+		}
+
+		return number
+	default:
+		return math.NaN()
+	}
+}
+
+func toBoolean(value any) bool {
+	switch value := value.(type) {
+	case float64:
+		return value != 0 && !math.IsNaN(value)
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case *array, builtInFunction, *declaredFunction:
+		return true
+	default:
+		return false
+	}
+}
+
+func toString(value any) string {
+	switch value := value.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(value):
+			return "NaN"
+		case math.IsInf(value, 1):
+			return "Infinity"
+		case math.IsInf(value, -1):
+			return "-Infinity"
+		case value == 0:
+			return "0"
+		}
+
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		return value
+	case undefined:
+		return "undefined"
+	case builtInFunction:
+		return "function () { [native code] }"
+	case *declaredFunction:
+		return fmt.Sprintf("function %s() { [code] }", value.declaration.Name.Value)
+	case *array:
+		elements := make([]string, len(value.elements))
+
+		for i, element := range value.elements {
+			elements[i] = toString(element)
+		}
+
+		return strings.Join(elements, ",")
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
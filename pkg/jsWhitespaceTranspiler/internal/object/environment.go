@@ -1,17 +1,37 @@
 package object
 
+// Environment is a lexical scope, blocks enclose the environment they are declared in
 type Environment struct {
-	store map[string]Object
+	store     map[string]Object
+	constants map[string]bool
+	outer     *Environment
 }
 
 func NewEnvironment() *Environment {
 	store := make(map[string]Object)
+	constants := make(map[string]bool)
 
-	return &Environment{store: store}
+	return &Environment{store: store, constants: constants}
+}
+
+func NewEnclosedEnvironment(outer *Environment) *Environment {
+	environment := NewEnvironment()
+	environment.outer = outer
+
+	return environment
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
+	if !ok && e.outer != nil {
+		return e.outer.Get(name)
+	}
+
+	return obj, ok
+}
+
+func (e *Environment) GetLocal(name string) (Object, bool) {
+	obj, ok := e.store[name]
 
 	return obj, ok
 }
@@ -21,3 +41,22 @@ func (e *Environment) Set(name string, val Object) Object {
 
 	return val
 }
+
+func (e *Environment) SetConstant(name string, val Object) Object {
+	e.constants[name] = true
+
+	return e.Set(name, val)
+}
+
+// IsConstant reports whether the closest declaration of name is a constant
+func (e *Environment) IsConstant(name string) bool {
+	if _, ok := e.store[name]; ok {
+		return e.constants[name]
+	}
+
+	if e.outer != nil {
+		return e.outer.IsConstant(name)
+	}
+
+	return false
+}
@@ -1,15 +1,20 @@
 package object
 
-import "github.com/pakut2/w-format/pkg/whitespace"
+import (
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+	"github.com/pakut2/w-format/pkg/whitespace"
+)
 
 type ObjectType string
 
 const (
 	PROGRAM_OBJ  = "PROGRAM"
 	STRING_OBJ   = "STRING"
-	CHAR_OBJ     = "CHAR"
 	INT_OBJ      = "INT"
+	BOOLEAN_OBJ  = "BOOLEAN"
+	ARRAY_OBJ    = "ARRAY"
 	BUILT_IN_OBJ = "BUILT_IN"
+	FUNCTION_OBJ = "FUNCTION"
 	VOID_OBJ     = "VOID"
 )
 
@@ -25,8 +30,11 @@ type Program struct {
 func (p *Program) Type() ObjectType                       { return PROGRAM_OBJ }
 func (p *Program) Instructions() []whitespace.Instruction { return p.WhitespaceInstructions }
 
+// String is either a constant known at compile time, or lives in a heap address holding the address of its heap block
 type String struct {
-	Chars []Char
+	Constant    bool
+	Value       string
+	HeapAddress int64
 }
 
 func (s *String) Type() ObjectType { return STRING_OBJ }
@@ -34,21 +42,34 @@ func (s *String) Instructions() []whitespace.Instruction {
 	return []whitespace.Instruction{}
 }
 
-type Char struct {
+type Integer struct {
 	HeapAddress int64
 }
 
-func (c *Char) Type() ObjectType { return CHAR_OBJ }
-func (c *Char) Instructions() []whitespace.Instruction {
+func (i *Integer) Type() ObjectType { return INT_OBJ }
+func (i *Integer) Instructions() []whitespace.Instruction {
 	return []whitespace.Instruction{}
 }
 
-type Integer struct {
+// Boolean lives in a heap address holding 1 when true and 0 when false
+type Boolean struct {
 	HeapAddress int64
 }
 
-func (i *Integer) Type() ObjectType { return INT_OBJ }
-func (i *Integer) Instructions() []whitespace.Instruction {
+func (b *Boolean) Type() ObjectType { return BOOLEAN_OBJ }
+func (b *Boolean) Instructions() []whitespace.Instruction {
+	return []whitespace.Instruction{}
+}
+
+// Array lives in a heap address holding the address of its header block, made of the length, the capacity and the address of the elements.
+// Element is an object of the type of the elements, nil until it is known.
+type Array struct {
+	HeapAddress int64
+	Element     Object
+}
+
+func (a *Array) Type() ObjectType { return ARRAY_OBJ }
+func (a *Array) Instructions() []whitespace.Instruction {
 	return []whitespace.Instruction{}
 }
 
@@ -61,7 +82,21 @@ func (b *BuiltIn) Instructions() []whitespace.Instruction {
 	return []whitespace.Instruction{}
 }
 
-type BuiltInFunction func(args ...Object) Object
+type BuiltInFunction func(args ...Object) (Object, error)
+
+// Function holds the types of its parameters and of its return value, empty until a call or a return statement sets them
+type Function struct {
+	Declaration    *ast.FunctionDeclaration
+	LabelId        int64
+	ReturnsValue   bool
+	ParameterTypes []ObjectType
+	ReturnType     ObjectType
+}
+
+func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
+func (f *Function) Instructions() []whitespace.Instruction {
+	return []whitespace.Instruction{}
+}
 
 type Void struct{}
 
@@ -1,10 +1,36 @@
 package object
 
+// ScopeContext is a stack of the statements enclosing the transpiled code, which break and continue statements can target
 type ScopeContext struct {
-	For *ForContext
+	Outer  *ScopeContext
+	Labels []string
+	Loop   *LoopContext
+
+	EndLabelId int64
 }
 
-type ForContext struct {
+type LoopContext struct {
 	ControlLabelId int64
-	EndLabelId     int64
+}
+
+func (s *ScopeContext) FindLoop() *ScopeContext {
+	for context := s; context != nil; context = context.Outer {
+		if context.Loop != nil {
+			return context
+		}
+	}
+
+	return nil
+}
+
+func (s *ScopeContext) FindLabel(label string) *ScopeContext {
+	for context := s; context != nil; context = context.Outer {
+		for _, contextLabel := range context.Labels {
+			if contextLabel == label {
+				return context
+			}
+		}
+	}
+
+	return nil
 }
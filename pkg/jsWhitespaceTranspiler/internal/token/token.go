@@ -2,10 +2,17 @@ package token
 
 type TokenType string
 
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
 type Token struct {
-	Type       TokenType
-	Literal    string
-	LineNumber int
+	Type    TokenType
+	Literal string
+	Start   Position
+	End     Position
 }
 
 const (
@@ -33,33 +40,53 @@ const (
 	DECREMENT             = "--"
 	AND                   = "&&"
 	OR                    = "||"
+	PLUS_ASSIGN           = "+="
+	MINUS_ASSIGN          = "-="
+	ASTERISK_ASSIGN       = "*="
+	SLASH_ASSIGN          = "/="
+	PERCENT_ASSIGN        = "%="
 
 	COMMA             = ","
+	COLON             = ":"
+	QUESTION          = "?"
+	DOT               = "."
 	SEMICOLON         = ";"
 	LEFT_PARENTHESIS  = "("
 	RIGHT_PARENTHESIS = ")"
 	LEFT_BRACE        = "{"
 	RIGHT_BRACE       = "}"
+	LEFT_BRACKET      = "["
+	RIGHT_BRACKET     = "]"
 
 	LET      = "LET"
+	CONST    = "CONST"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	FOR      = "FOR"
+	WHILE    = "WHILE"
+	DO       = "DO"
 	BREAK    = "BREAK"
 	CONTINUE = "CONTINUE"
+	FUNCTION = "FUNCTION"
+	RETURN   = "RETURN"
 )
 
 var keywords = map[string]TokenType{
 	"let":      LET,
+	"const":    CONST,
 	"true":     TRUE,
 	"false":    FALSE,
 	"if":       IF,
 	"else":     ELSE,
 	"for":      FOR,
+	"while":    WHILE,
+	"do":       DO,
 	"break":    BREAK,
 	"continue": CONTINUE,
+	"function": FUNCTION,
+	"return":   RETURN,
 }
 
 func LookupIdentifier(identifier string) TokenType {
@@ -70,10 +97,10 @@ func LookupIdentifier(identifier string) TokenType {
 	return IDENTIFIER
 }
 
-func NewTokenFromChar(tokenType TokenType, char rune, lineNumber int) Token {
-	return Token{Type: tokenType, Literal: string(char), LineNumber: lineNumber}
+func NewTokenFromChar(tokenType TokenType, char rune) Token {
+	return Token{Type: tokenType, Literal: string(char)}
 }
 
-func NewTokenFromString(tokenType TokenType, literal string, lineNumber int) Token {
-	return Token{Type: tokenType, Literal: literal, LineNumber: lineNumber}
+func NewTokenFromString(tokenType TokenType, literal string) Token {
+	return Token{Type: tokenType, Literal: literal}
 }
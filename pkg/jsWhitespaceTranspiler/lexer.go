@@ -6,53 +6,120 @@ import (
 	"io"
 
 	"github.com/pakut2/w-format/internal/utilities"
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
 	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/token"
 )
 
 type Lexer struct {
-	input bufio.Reader
-
-	previousChar      rune
-	currentChar       rune
-	currentLineNumber int
+	input    bufio.Reader
+	fileName string
+	errors   Diagnostics
+
+	previousChar    rune
+	currentChar     rune
+	currentCharSize int
+	position        token.Position
 }
 
 func NewLexer(input io.Reader) *Lexer {
-	l := &Lexer{input: *bufio.NewReader(input), currentLineNumber: 1}
+	return NewNamedLexer(input, "")
+}
+
+func NewNamedLexer(input io.Reader, fileName string) *Lexer {
+	l := &Lexer{input: *bufio.NewReader(input), fileName: fileName, position: token.Position{Line: 1, Column: 1}}
 	l.readChar()
 
 	return l
 }
 
+func (l *Lexer) Errors() Diagnostics {
+	return l.errors
+}
+
+func (l *Lexer) addError(start token.Position, format string, args ...any) {
+	span := ast.Span{Start: start, End: l.position}
+
+	l.errors = append(l.errors, newDiagnostic(l.fileName, span, ERROR, format, args...))
+}
+
 func (l *Lexer) readChar() {
 	l.previousChar = l.currentChar
-	l.currentChar = utilities.ReadRune(&l.input)
+
+	l.position.Offset += l.currentCharSize
+
+	if l.currentChar == '\n' {
+		l.position.Line++
+		l.position.Column = 1
+	} else if l.currentCharSize > 0 {
+		l.position.Column++
+	}
+
+	char, size, err := utilities.ReadRune(&l.input)
+	if err != nil {
+		l.addError(l.position, "%v", err)
+	}
+
+	l.currentChar = char
+	l.currentCharSize = size
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var currentToken token.Token
-
 	l.skipWhitespace()
 
+	for l.currentChar == '/' && (utilities.PeekRune(l.input) == '/' || utilities.PeekRune(l.input) == '*') {
+		l.skipComment()
+		l.skipWhitespace()
+	}
+
+	start := l.position
+
+	currentToken := l.readToken()
+	currentToken.Start = start
+	currentToken.End = l.position
+
+	return currentToken
+}
+
+func (l *Lexer) readToken() token.Token {
+	var currentToken token.Token
+
 	switch l.currentChar {
 	case '*':
-		currentToken = token.NewTokenFromChar(token.ASTERISK, l.currentChar, l.currentLineNumber)
+		if utilities.PeekRune(l.input) == '=' {
+			currentToken = l.readTwoCharToken(token.ASTERISK_ASSIGN)
+		} else {
+			currentToken = token.NewTokenFromChar(token.ASTERISK, l.currentChar)
+		}
 	case '%':
-		currentToken = token.NewTokenFromChar(token.PERCENT, l.currentChar, l.currentLineNumber)
+		if utilities.PeekRune(l.input) == '=' {
+			currentToken = l.readTwoCharToken(token.PERCENT_ASSIGN)
+		} else {
+			currentToken = token.NewTokenFromChar(token.PERCENT, l.currentChar)
+		}
 	case ';':
-		currentToken = token.NewTokenFromChar(token.SEMICOLON, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.SEMICOLON, l.currentChar)
 	case ',':
-		currentToken = token.NewTokenFromChar(token.COMMA, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.COMMA, l.currentChar)
+	case ':':
+		currentToken = token.NewTokenFromChar(token.COLON, l.currentChar)
+	case '?':
+		currentToken = token.NewTokenFromChar(token.QUESTION, l.currentChar)
+	case '.':
+		currentToken = token.NewTokenFromChar(token.DOT, l.currentChar)
 	case '(':
-		currentToken = token.NewTokenFromChar(token.LEFT_PARENTHESIS, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.LEFT_PARENTHESIS, l.currentChar)
 	case ')':
-		currentToken = token.NewTokenFromChar(token.RIGHT_PARENTHESIS, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.RIGHT_PARENTHESIS, l.currentChar)
 	case '{':
-		currentToken = token.NewTokenFromChar(token.LEFT_BRACE, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.LEFT_BRACE, l.currentChar)
 	case '}':
-		currentToken = token.NewTokenFromChar(token.RIGHT_BRACE, l.currentChar, l.currentLineNumber)
+		currentToken = token.NewTokenFromChar(token.RIGHT_BRACE, l.currentChar)
+	case '[':
+		currentToken = token.NewTokenFromChar(token.LEFT_BRACKET, l.currentChar)
+	case ']':
+		currentToken = token.NewTokenFromChar(token.RIGHT_BRACKET, l.currentChar)
 	case '"', '\'', '`':
-		currentToken = token.NewTokenFromString(token.STRING, l.readString(), l.currentLineNumber)
+		currentToken = token.NewTokenFromString(token.STRING, l.readString())
 	case '&':
 		if utilities.PeekRune(l.input) == '&' {
 			startingChar := l.currentChar
@@ -61,10 +128,9 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.AND,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar)
 		}
 	case '|':
 		if utilities.PeekRune(l.input) == '|' {
@@ -74,10 +140,9 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.OR,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar)
 		}
 	case '+':
 		if utilities.PeekRune(l.input) == '+' {
@@ -87,10 +152,11 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.INCREMENT,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
+		} else if utilities.PeekRune(l.input) == '=' {
+			currentToken = l.readTwoCharToken(token.PLUS_ASSIGN)
 		} else {
-			currentToken = token.NewTokenFromChar(token.PLUS, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.PLUS, l.currentChar)
 		}
 	case '-':
 		if utilities.PeekRune(l.input) == '-' {
@@ -100,18 +166,18 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.DECREMENT,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
+		} else if utilities.PeekRune(l.input) == '=' {
+			currentToken = l.readTwoCharToken(token.MINUS_ASSIGN)
 		} else {
-			currentToken = token.NewTokenFromChar(token.MINUS, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.MINUS, l.currentChar)
 		}
 	case '/':
-		nextChar := utilities.PeekRune(l.input)
-		if nextChar == '/' || nextChar == '*' {
-			panic("comments are a violation of DRY")
+		if utilities.PeekRune(l.input) == '=' {
+			currentToken = l.readTwoCharToken(token.SLASH_ASSIGN)
+		} else {
+			currentToken = token.NewTokenFromChar(token.SLASH, l.currentChar)
 		}
-
-		currentToken = token.NewTokenFromChar(token.SLASH, l.currentChar, l.currentLineNumber)
 	case '=':
 		nextChars, err := utilities.PeekTwoRunes(l.input)
 		if err == nil && nextChars == "==" {
@@ -122,10 +188,9 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.EQUALS,
 				fmt.Sprintf("%c%s", startingChar, nextChars),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.ASSIGN, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.ASSIGN, l.currentChar)
 		}
 	case '!':
 		nextChars, err := utilities.PeekTwoRunes(l.input)
@@ -137,10 +202,9 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.NOT_EQUALS,
 				fmt.Sprintf("%c%s", startingChar, nextChars),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.BANG, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.BANG, l.currentChar)
 		}
 	case '<':
 		if utilities.PeekRune(l.input) == '=' {
@@ -150,10 +214,9 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.LESS_THAN_OR_EQUAL,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.LESS_THAN, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.LESS_THAN, l.currentChar)
 		}
 	case '>':
 		if utilities.PeekRune(l.input) == '=' {
@@ -163,24 +226,22 @@ func (l *Lexer) NextToken() token.Token {
 			currentToken = token.NewTokenFromString(
 				token.GREATER_THAN_OR_EQUAL,
 				fmt.Sprintf("%c%c", startingChar, l.currentChar),
-				l.currentLineNumber,
 			)
 		} else {
-			currentToken = token.NewTokenFromChar(token.GREATER_THAN, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.GREATER_THAN, l.currentChar)
 		}
 	case 0:
-		currentToken = token.NewTokenFromString(token.EOF, "", l.currentLineNumber)
+		currentToken = token.NewTokenFromString(token.EOF, "")
 	default:
 		if l.isLetter() {
 			currentToken.Literal = l.readIdentifier()
 			currentToken.Type = token.LookupIdentifier(currentToken.Literal)
-			currentToken.LineNumber = l.currentLineNumber
 
 			return currentToken
 		} else if l.isDigit() {
-			return token.NewTokenFromString(token.INT, l.readNumber(), l.currentLineNumber)
+			return token.NewTokenFromString(token.INT, l.readNumber())
 		} else {
-			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar, l.currentLineNumber)
+			currentToken = token.NewTokenFromChar(token.ILLEGAL, l.currentChar)
 		}
 	}
 
@@ -189,17 +250,47 @@ func (l *Lexer) NextToken() token.Token {
 	return currentToken
 }
 
+func (l *Lexer) readTwoCharToken(tokenType token.TokenType) token.Token {
+	startingChar := l.currentChar
+	l.readChar()
+
+	return token.NewTokenFromString(tokenType, fmt.Sprintf("%c%c", startingChar, l.currentChar))
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.currentChar == ' ' || l.currentChar == '\t' || l.currentChar == '\n' || l.currentChar == '\r' {
-		if l.currentChar == '\n' {
-			l.currentLineNumber++
+		l.readChar()
+	}
+}
+
+func (l *Lexer) skipComment() {
+	start := l.position
+
+	l.readChar()
+
+	if l.currentChar == '/' {
+		for l.currentChar != 0 && l.currentChar != '\n' {
+			l.readChar()
 		}
 
+		l.addError(start, "comments are a violation of DRY")
+
+		return
+	}
+
+	l.readChar()
+
+	for l.currentChar != 0 && (l.previousChar != '*' || l.currentChar != '/') {
 		l.readChar()
 	}
+
+	l.readChar()
+
+	l.addError(start, "comments are a violation of DRY")
 }
 
 func (l *Lexer) readString() string {
+	start := l.position
 	startingQuote := l.currentChar
 
 	var stringLiteral string
@@ -207,7 +298,13 @@ func (l *Lexer) readString() string {
 	for {
 		l.readChar()
 
-		if l.currentChar == 0 || (l.currentChar == startingQuote && l.previousChar != '\\') {
+		if l.currentChar == 0 {
+			l.addError(start, "unterminated string literal")
+
+			break
+		}
+
+		if l.currentChar == startingQuote && l.previousChar != '\\' {
 			break
 		}
 
@@ -222,7 +319,7 @@ func (l *Lexer) readString() string {
 }
 
 func (l *Lexer) isLetter() bool {
-	return 'a' <= l.currentChar && l.currentChar <= 'z' || 'A' <= l.currentChar && l.currentChar <= 'Z' || l.currentChar == '_' || l.currentChar == '.'
+	return 'a' <= l.currentChar && l.currentChar <= 'z' || 'A' <= l.currentChar && l.currentChar <= 'Z' || l.currentChar == '_'
 }
 
 func (l *Lexer) readIdentifier() string {
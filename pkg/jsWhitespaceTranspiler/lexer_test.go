@@ -33,7 +33,9 @@ for (let i = 0; i < 10; i++) {
 `
 
 	expectedTokens := []token.Token{
-		{Type: token.IDENTIFIER, Literal: "console.log"},
+		{Type: token.IDENTIFIER, Literal: "console"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.IDENTIFIER, Literal: "log"},
 		{Type: token.LEFT_PARENTHESIS, Literal: "("},
 		{Type: token.STRING, Literal: "Hello"},
 		{Type: token.COMMA, Literal: ","},
@@ -56,7 +58,9 @@ for (let i = 0; i < 10; i++) {
 		{Type: token.ASSIGN, Literal: "="},
 		{Type: token.IDENTIFIER, Literal: "number1"},
 		{Type: token.SEMICOLON, Literal: ";"},
-		{Type: token.IDENTIFIER, Literal: "console.log"},
+		{Type: token.IDENTIFIER, Literal: "console"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.IDENTIFIER, Literal: "log"},
 		{Type: token.LEFT_PARENTHESIS, Literal: "("},
 		{Type: token.IDENTIFIER, Literal: "text"},
 		{Type: token.COMMA, Literal: ","},
@@ -153,3 +157,48 @@ for (let i = 0; i < 10; i++) {
 		}
 	}
 }
+
+func TestLexerPositions(t *testing.T) {
+	input := "let a = 1;\n\tlet ąb = 'x';"
+
+	expectedTokens := []token.Token{
+		{Type: token.LET, Literal: "let", Start: token.Position{Offset: 0, Line: 1, Column: 1}, End: token.Position{Offset: 3, Line: 1, Column: 4}},
+		{Type: token.IDENTIFIER, Literal: "a", Start: token.Position{Offset: 4, Line: 1, Column: 5}, End: token.Position{Offset: 5, Line: 1, Column: 6}},
+		{Type: token.ASSIGN, Literal: "=", Start: token.Position{Offset: 6, Line: 1, Column: 7}, End: token.Position{Offset: 7, Line: 1, Column: 8}},
+		{Type: token.INT, Literal: "1", Start: token.Position{Offset: 8, Line: 1, Column: 9}, End: token.Position{Offset: 9, Line: 1, Column: 10}},
+		{Type: token.SEMICOLON, Literal: ";", Start: token.Position{Offset: 9, Line: 1, Column: 10}, End: token.Position{Offset: 10, Line: 1, Column: 11}},
+		{Type: token.LET, Literal: "let", Start: token.Position{Offset: 12, Line: 2, Column: 2}, End: token.Position{Offset: 15, Line: 2, Column: 5}},
+		{Type: token.ILLEGAL, Literal: "ą", Start: token.Position{Offset: 16, Line: 2, Column: 6}, End: token.Position{Offset: 18, Line: 2, Column: 7}},
+		{Type: token.IDENTIFIER, Literal: "b", Start: token.Position{Offset: 18, Line: 2, Column: 7}, End: token.Position{Offset: 19, Line: 2, Column: 8}},
+		{Type: token.ASSIGN, Literal: "=", Start: token.Position{Offset: 20, Line: 2, Column: 9}, End: token.Position{Offset: 21, Line: 2, Column: 10}},
+		{Type: token.STRING, Literal: "x", Start: token.Position{Offset: 22, Line: 2, Column: 11}, End: token.Position{Offset: 25, Line: 2, Column: 14}},
+		{Type: token.SEMICOLON, Literal: ";", Start: token.Position{Offset: 25, Line: 2, Column: 14}, End: token.Position{Offset: 26, Line: 2, Column: 15}},
+		{Type: token.EOF, Literal: "", Start: token.Position{Offset: 26, Line: 2, Column: 15}, End: token.Position{Offset: 26, Line: 2, Column: 15}},
+	}
+
+	lexer := NewLexer(strings.NewReader(input))
+
+	for i, expectedToken := range expectedTokens {
+		if parsedToken := lexer.NextToken(); parsedToken != expectedToken {
+			t.Errorf("token (#%d) incorrect. expected=%+v, got=%+v", i+1, expectedToken, parsedToken)
+		}
+	}
+}
+
+func TestLexerAssignmentOperators(t *testing.T) {
+	input := `a += b -= c *= d /= e %= f++ - --g = h / 2`
+
+	expectedTypes := []token.TokenType{
+		token.IDENTIFIER, token.PLUS_ASSIGN, token.IDENTIFIER, token.MINUS_ASSIGN, token.IDENTIFIER, token.ASTERISK_ASSIGN,
+		token.IDENTIFIER, token.SLASH_ASSIGN, token.IDENTIFIER, token.PERCENT_ASSIGN, token.IDENTIFIER, token.INCREMENT,
+		token.MINUS, token.DECREMENT, token.IDENTIFIER, token.ASSIGN, token.IDENTIFIER, token.SLASH, token.INT, token.EOF,
+	}
+
+	lexer := NewLexer(strings.NewReader(input))
+
+	for i, expectedType := range expectedTypes {
+		if parsedToken := lexer.NextToken(); parsedToken.Type != expectedType {
+			t.Errorf("token (#%d) type incorrect. expected=%s, got=%s", i+1, expectedType, parsedToken.Type)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package jsWhitespaceTranspiler
+
+import "github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
+
+// variableLastUses maps the index of a statement to the variables declared in the statement list, that are not mentioned after it.
+// Their heap addresses can be reused by variables declared later.
+func variableLastUses(statements []ast.Statement) map[int][]string {
+	mentions := make([]map[string]bool, len(statements))
+
+	for i, statement := range statements {
+		mentions[i] = make(map[string]bool)
+		mentionedNames(statement, mentions[i])
+	}
+
+	lastUses := make(map[int][]string)
+
+	for i, statement := range statements {
+		declaration, ok := statement.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+
+		lastUse := i
+
+		for j := len(statements) - 1; j > i; j-- {
+			if mentions[j][declaration.Name.Value] {
+				lastUse = j
+
+				break
+			}
+		}
+
+		lastUses[lastUse] = append(lastUses[lastUse], declaration.Name.Value)
+	}
+
+	return lastUses
+}
+
+// mentionedNames collects the identifiers used in the node, regardless of the scope they resolve to
+func mentionedNames(node ast.Node, names map[string]bool) {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		names[node.Value] = true
+	case *ast.LetStatement:
+		mentionedNames(node.Name, names)
+		mentionedNames(node.Value, names)
+	case *ast.AssignmentStatement:
+		mentionedNames(node.Name, names)
+		mentionedNames(node.Value, names)
+	case *ast.CompoundAssignmentStatement:
+		mentionedNames(node.Target, names)
+		mentionedNames(node.Value, names)
+	case *ast.IndexAssignmentStatement:
+		mentionedNames(node.Target, names)
+		mentionedNames(node.Value, names)
+	case *ast.IfStatement:
+		mentionedNames(node.Condition, names)
+		mentionedNames(node.Consequence, names)
+
+		if node.Alternative != nil {
+			mentionedNames(node.Alternative, names)
+		}
+	case *ast.BlockStatement:
+		for _, statement := range node.Statements {
+			mentionedNames(statement, names)
+		}
+	case *ast.ForStatement:
+		mentionedNames(node.Declaration, names)
+		mentionedNames(node.Boundary, names)
+		mentionedNames(node.Increment, names)
+		mentionedNames(node.Body, names)
+	case *ast.WhileStatement:
+		mentionedNames(node.Condition, names)
+		mentionedNames(node.Body, names)
+	case *ast.DoWhileStatement:
+		mentionedNames(node.Body, names)
+		mentionedNames(node.Condition, names)
+	case *ast.LabeledStatement:
+		mentionedNames(node.Body, names)
+	case *ast.FunctionDeclaration:
+		mentionedNames(node.Body, names)
+	case *ast.ReturnStatement:
+		if node.Value != nil {
+			mentionedNames(node.Value, names)
+		}
+	case *ast.ExpressionStatement:
+		mentionedNames(node.Expression, names)
+	case *ast.PrefixExpression:
+		mentionedNames(node.Right, names)
+	case *ast.InfixExpression:
+		mentionedNames(node.Left, names)
+		mentionedNames(node.Right, names)
+	case *ast.ConditionalExpression:
+		mentionedNames(node.Condition, names)
+		mentionedNames(node.Consequence, names)
+		mentionedNames(node.Alternative, names)
+	case *ast.SuffixExpression:
+		mentionedNames(node.Left, names)
+	case *ast.CallExpression:
+		mentionedNames(node.Function, names)
+
+		for _, argument := range node.Arguments {
+			mentionedNames(argument, names)
+		}
+	case *ast.IndexExpression:
+		mentionedNames(node.Left, names)
+		mentionedNames(node.Index, names)
+	case *ast.MemberExpression:
+		mentionedNames(node.Object, names)
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			mentionedNames(element, names)
+		}
+	}
+}
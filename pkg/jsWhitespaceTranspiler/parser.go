@@ -1,7 +1,6 @@
 package jsWhitespaceTranspiler
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
@@ -16,7 +15,9 @@ type (
 const (
 	_ int = iota
 	LOWEST
-	LOGICAL
+	CONDITIONAL
+	LOGICAL_OR
+	LOGICAL_AND
 	EQUALS
 	LESS_GREATER
 	SUM
@@ -24,11 +25,22 @@ const (
 	PREFIX
 	SUFFIX
 	CALL
+	MEMBER
 )
 
+// compoundAssignmentOperators maps compound assignment tokens to the arithmetic operator they apply
+var compoundAssignmentOperators = map[token.TokenType]string{
+	token.PLUS_ASSIGN:     ast.ADDITION,
+	token.MINUS_ASSIGN:    ast.SUBTRACTION,
+	token.ASTERISK_ASSIGN: ast.MULTIPLICATION,
+	token.SLASH_ASSIGN:    ast.DIVISION,
+	token.PERCENT_ASSIGN:  ast.MODULO,
+}
+
 var precedences = map[token.TokenType]int{
-	token.AND:                   LOGICAL,
-	token.OR:                    LOGICAL,
+	token.QUESTION:              CONDITIONAL,
+	token.AND:                   LOGICAL_AND,
+	token.OR:                    LOGICAL_OR,
 	token.EQUALS:                EQUALS,
 	token.NOT_EQUALS:            EQUALS,
 	token.LESS_THAN:             LESS_GREATER,
@@ -43,11 +55,13 @@ var precedences = map[token.TokenType]int{
 	token.INCREMENT:             SUFFIX,
 	token.DECREMENT:             SUFFIX,
 	token.LEFT_PARENTHESIS:      CALL,
+	token.LEFT_BRACKET:          MEMBER,
+	token.DOT:                   MEMBER,
 }
 
 type Parser struct {
 	l      *Lexer
-	errors []string
+	errors Diagnostics
 
 	currentToken token.Token
 	peekToken    token.Token
@@ -57,7 +71,7 @@ type Parser struct {
 }
 
 func NewParser(l *Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}}
+	p := &Parser{l: l, errors: Diagnostics{}}
 
 	p.nextToken()
 	p.nextToken()
@@ -69,11 +83,16 @@ func NewParser(l *Lexer) *Parser {
 	p.registerPrefixFunc(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefixFunc(token.LEFT_PARENTHESIS, p.parseGroupedExpression)
 	p.registerPrefixFunc(token.BANG, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.INCREMENT, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.DECREMENT, p.parsePrefixExpression)
 	p.registerPrefixFunc(token.TRUE, p.parseBoolean)
 	p.registerPrefixFunc(token.FALSE, p.parseBoolean)
+	p.registerPrefixFunc(token.LEFT_BRACKET, p.parseArrayLiteral)
 
 	p.infixParseFuncs = make(map[token.TokenType]infixParseFunc)
 	p.registerInfixFunc(token.LEFT_PARENTHESIS, p.parseCallExpression)
+	p.registerInfixFunc(token.LEFT_BRACKET, p.parseIndexExpression)
+	p.registerInfixFunc(token.DOT, p.parseMemberExpression)
 	p.registerInfixFunc(token.PLUS, p.parseInfixExpression)
 	p.registerInfixFunc(token.MINUS, p.parseInfixExpression)
 	p.registerInfixFunc(token.ASTERISK, p.parseInfixExpression)
@@ -87,6 +106,7 @@ func NewParser(l *Lexer) *Parser {
 	p.registerInfixFunc(token.GREATER_THAN_OR_EQUAL, p.parseInfixExpression)
 	p.registerInfixFunc(token.AND, p.parseInfixExpression)
 	p.registerInfixFunc(token.OR, p.parseInfixExpression)
+	p.registerInfixFunc(token.QUESTION, p.parseConditionalExpression)
 	p.registerInfixFunc(token.INCREMENT, p.parseSuffixExpression)
 	p.registerInfixFunc(token.DECREMENT, p.parseSuffixExpression)
 
@@ -106,8 +126,8 @@ func (p *Parser) registerInfixFunc(TokenType token.TokenType, function infixPars
 	p.infixParseFuncs[TokenType] = function
 }
 
-func (p *Parser) ParseProgram() *ast.Program {
-	program := &ast.Program{}
+func (p *Parser) ParseProgram() (*ast.Program, error) {
+	program := &ast.Program{FileName: p.l.fileName}
 	program.Statements = []ast.Statement{}
 
 	for !p.currentTokenIs(token.EOF) {
@@ -120,26 +140,64 @@ func (p *Parser) ParseProgram() *ast.Program {
 		p.nextToken()
 	}
 
-	return program
+	if len(program.Statements) > 0 {
+		program.Span = ast.Span{
+			Start: program.Statements[0].NodeSpan().Start,
+			End:   program.Statements[len(program.Statements)-1].NodeSpan().End,
+		}
+	}
+
+	errors := append(p.l.Errors(), p.errors...).sorted()
+	if errors.HasErrors() {
+		return program, errors
+	}
+
+	return program, nil
+}
+
+func (p *Parser) addError(span ast.Span, format string, args ...any) {
+	p.errors = append(p.errors, newDiagnostic(p.l.fileName, span, ERROR, format, args...))
+
+	panic(bailout{})
+}
+
+func (p *Parser) spanFrom(start token.Position) ast.Span {
+	return ast.Span{Start: start, End: p.currentToken.End}
 }
 
 func (p *Parser) parseStatement() ast.Statement {
+	defer recoverBailout(p.skipStatement)
+
 	switch p.currentToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.IF:
 		return p.parseIfStatement()
 	case token.FOR:
 		return p.parseForStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.DO:
+		return p.parseDoWhileStatement()
 	case token.BREAK:
 		return p.parseBreakStatement()
 	case token.CONTINUE:
 		return p.parseContinueStatement()
+	case token.FUNCTION:
+		return p.parseFunctionDeclaration()
+	case token.RETURN:
+		return p.parseReturnStatement()
+	case token.LEFT_BRACE:
+		return p.parseBlockStatement()
 	default:
 		if p.currentTokenIs(token.IDENTIFIER) && p.peekTokenIs(token.ASSIGN) {
 			return p.parseAssignmentStatement()
 		}
 
+		if p.currentTokenIs(token.IDENTIFIER) && p.peekTokenIs(token.COLON) {
+			return p.parseLabeledStatement()
+		}
+
 		return p.parseExpressionStatement()
 	}
 }
@@ -148,11 +206,12 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	statement := &ast.LetStatement{Token: p.currentToken}
 
 	if !p.expectPeek(token.IDENTIFIER) {
-		panic(fmt.Sprintf("[:%d] invalid declaration statement, identifier must follow %q", p.currentToken.LineNumber, p.currentToken.Type))
+		p.addError(ast.TokenSpan(p.currentToken), "invalid declaration statement, identifier must follow %q", p.currentToken.Type)
 	}
 
-	statement.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	statement.Name = p.parseIdentifier().(*ast.Identifier)
 	statement.Value = p.parseAssignmentStatement().Value
+	statement.Span = p.spanFrom(statement.Token.Start)
 
 	return statement
 }
@@ -160,26 +219,28 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 func (p *Parser) parseAssignmentStatement() *ast.AssignmentStatement {
 	statement := &ast.AssignmentStatement{Token: p.currentToken}
 
-	statement.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	statement.Name = p.parseIdentifier().(*ast.Identifier)
 
 	if !p.expectPeek(token.ASSIGN) {
-		panic(fmt.Sprintf("[:%d] invalid assignment statement, assignment must follow %q", p.currentToken.LineNumber, p.currentToken.Type))
+		p.addError(ast.TokenSpan(p.currentToken), "invalid assignment statement, assignment must follow %q", p.currentToken.Type)
 	}
 
 	p.nextToken()
 	statement.Value = p.parseExpression(LOWEST)
 
-	expressionLineNumber := p.currentToken.LineNumber
+	expressionToken := p.currentToken
 
 	for !p.currentTokenIs(token.SEMICOLON) {
 		// TODO check end of line
 		if p.currentTokenIs(token.EOF) {
-			panic(fmt.Sprintf("[:%d] missing semicolon at the end of assignment statement", expressionLineNumber))
+			p.addError(ast.TokenSpan(expressionToken), "missing semicolon at the end of assignment statement")
 		}
 
 		p.nextToken()
 	}
 
+	statement.Span = p.spanFrom(statement.Token.Start)
+
 	return statement
 }
 
@@ -212,6 +273,8 @@ func (p *Parser) parseIfStatement() ast.Statement {
 		statement.Alternative = p.parseBlockStatement()
 	}
 
+	statement.Span = p.spanFrom(statement.Token.Start)
+
 	return statement
 }
 
@@ -230,6 +293,8 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		p.nextToken()
 	}
 
+	block.Span = p.spanFrom(block.Token.Start)
+
 	return block
 }
 
@@ -251,7 +316,7 @@ func (p *Parser) parseForStatement() ast.Statement {
 	}
 
 	p.nextToken()
-	statement.Increment = p.parseExpression(LOWEST)
+	statement.Increment = p.parseForIncrement()
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
 		return nil
@@ -262,6 +327,64 @@ func (p *Parser) parseForStatement() ast.Statement {
 	}
 
 	statement.Body = p.parseBlockStatement()
+	statement.Span = p.spanFrom(statement.Token.Start)
+
+	return statement
+}
+
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	statement := &ast.WhileStatement{Token: p.currentToken}
+
+	p.expectPeek(token.LEFT_PARENTHESIS)
+
+	p.nextToken()
+	statement.Condition = p.parseExpression(LOWEST)
+
+	p.expectPeek(token.RIGHT_PARENTHESIS)
+	p.expectPeek(token.LEFT_BRACE)
+
+	statement.Body = p.parseBlockStatement()
+	statement.Span = p.spanFrom(statement.Token.Start)
+
+	return statement
+}
+
+func (p *Parser) parseDoWhileStatement() *ast.DoWhileStatement {
+	statement := &ast.DoWhileStatement{Token: p.currentToken}
+
+	p.expectPeek(token.LEFT_BRACE)
+	statement.Body = p.parseBlockStatement()
+
+	p.expectPeek(token.WHILE)
+	p.expectPeek(token.LEFT_PARENTHESIS)
+
+	p.nextToken()
+	statement.Condition = p.parseExpression(LOWEST)
+
+	p.expectPeek(token.RIGHT_PARENTHESIS)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	statement.Span = p.spanFrom(statement.Token.Start)
+
+	return statement
+}
+
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	statement := &ast.LabeledStatement{Token: p.currentToken}
+	statement.Label = p.parseIdentifier().(*ast.Identifier)
+
+	p.nextToken()
+	p.nextToken()
+
+	statement.Body = p.parseStatement()
+	if statement.Body == nil {
+		return nil
+	}
+
+	statement.Span = p.spanFrom(statement.Token.Start)
 
 	return statement
 }
@@ -269,40 +392,180 @@ func (p *Parser) parseForStatement() ast.Statement {
 func (p *Parser) parseBreakStatement() *ast.BreakStatement {
 	statement := &ast.BreakStatement{Token: p.currentToken}
 
+	if p.peekTokenIs(token.IDENTIFIER) {
+		p.nextToken()
+		statement.Label = p.parseIdentifier().(*ast.Identifier)
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	statement.Span = p.spanFrom(statement.Token.Start)
+
 	return statement
 }
 
 func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
 	statement := &ast.ContinueStatement{Token: p.currentToken}
 
+	if p.peekTokenIs(token.IDENTIFIER) {
+		p.nextToken()
+		statement.Label = p.parseIdentifier().(*ast.Identifier)
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	statement.Span = p.spanFrom(statement.Token.Start)
+
 	return statement
 }
 
-func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+func (p *Parser) parseFunctionDeclaration() *ast.FunctionDeclaration {
+	declaration := &ast.FunctionDeclaration{Token: p.currentToken}
+
+	p.expectPeek(token.IDENTIFIER)
+	declaration.Name = p.parseIdentifier().(*ast.Identifier)
+
+	p.expectPeek(token.LEFT_PARENTHESIS)
+	declaration.Parameters = p.parseFunctionParameters()
+
+	p.expectPeek(token.LEFT_BRACE)
+	declaration.Body = p.parseBlockStatement()
+	declaration.Span = p.spanFrom(declaration.Token.Start)
+
+	return declaration
+}
+
+func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+	var parameters []*ast.Identifier
+
+	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+		p.nextToken()
+
+		return parameters
+	}
+
+	p.expectPeek(token.IDENTIFIER)
+	parameters = append(parameters, p.parseIdentifier().(*ast.Identifier))
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+
+		p.expectPeek(token.IDENTIFIER)
+		parameters = append(parameters, p.parseIdentifier().(*ast.Identifier))
+	}
+
+	p.expectPeek(token.RIGHT_PARENTHESIS)
+
+	return parameters
+}
+
+func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
+	statement := &ast.ReturnStatement{Token: p.currentToken}
+
+	if !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken()
+		statement.Value = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	statement.Span = p.spanFrom(statement.Token.Start)
+
+	return statement
+}
+
+// parseForIncrement parses the statement updating the loop, which is not followed by a semicolon, e.g. i++ or i = i + 2
+func (p *Parser) parseForIncrement() ast.Statement {
+	if !p.currentTokenIs(token.IDENTIFIER) || !p.peekTokenIs(token.ASSIGN) {
+		return p.parseExpressionStatement()
+	}
+
+	statement := &ast.AssignmentStatement{Token: p.currentToken}
+	statement.Name = p.parseIdentifier().(*ast.Identifier)
+
+	p.nextToken()
+	p.nextToken()
+	statement.Value = p.parseExpression(LOWEST)
+	statement.Span = p.spanFrom(statement.Token.Start)
+
+	return statement
+}
+
+func (p *Parser) parseExpressionStatement() ast.Statement {
 	statement := &ast.ExpressionStatement{}
 	statement.Expression = p.parseExpression(LOWEST)
 
+	if target, ok := statement.Expression.(*ast.IndexExpression); ok && p.peekTokenIs(token.ASSIGN) {
+		return p.parseIndexAssignmentStatement(target)
+	}
+
+	if _, ok := compoundAssignmentOperators[p.peekToken.Type]; ok {
+		return p.parseCompoundAssignmentStatement(statement.Expression)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	statement.Span = p.spanFrom(statement.Expression.NodeSpan().Start)
+
+	return statement
+}
+
+func (p *Parser) parseCompoundAssignmentStatement(target ast.Expression) *ast.CompoundAssignmentStatement {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		p.addError(target.NodeSpan(), "invalid compound assignment target")
+	}
+
+	p.nextToken()
+
+	statement := &ast.CompoundAssignmentStatement{
+		Token:    p.currentToken,
+		Target:   target,
+		Operator: compoundAssignmentOperators[p.currentToken.Type],
+	}
+
+	p.nextToken()
+	statement.Value = p.parseExpression(LOWEST)
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	statement.Span = p.spanFrom(target.NodeSpan().Start)
+
+	return statement
+}
+
+func (p *Parser) parseIndexAssignmentStatement(target *ast.IndexExpression) *ast.IndexAssignmentStatement {
+	p.nextToken()
+
+	statement := &ast.IndexAssignmentStatement{Token: p.currentToken, Target: target}
+
+	p.nextToken()
+	statement.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	statement.Span = p.spanFrom(target.NodeSpan().Start)
+
 	return statement
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFuncs[p.currentToken.Type]
 	if prefix == nil {
-		panic(fmt.Sprintf("[:%d] invalid expression token %s", p.currentToken.LineNumber, p.currentToken.Type))
-
-		return nil
+		p.addError(ast.TokenSpan(p.currentToken), "invalid expression token %s", p.currentToken.Type)
 	}
 
 	leftExpression := prefix()
@@ -329,6 +592,7 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 
 	p.nextToken()
 	expression.Right = p.parseExpression(PREFIX)
+	expression.Span = p.spanFrom(expression.Token.Start)
 
 	return expression
 }
@@ -344,12 +608,30 @@ func (p *Parser) parseInfixExpression(leftExpression ast.Expression) ast.Express
 
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
+	expression.Span = p.spanFrom(leftExpression.NodeSpan().Start)
+
+	return expression
+}
+
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{Token: p.currentToken, Condition: condition}
+
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+
+	p.expectPeek(token.COLON)
+
+	// Conditional expressions are right associative, the alternative can be another one
+	p.nextToken()
+	expression.Alternative = p.parseExpression(LOWEST)
+	expression.Span = p.spanFrom(condition.NodeSpan().Start)
 
 	return expression
 }
 
 func (p *Parser) parseSuffixExpression(leftExpression ast.Expression) ast.Expression {
 	return &ast.SuffixExpression{
+		Span:     p.spanFrom(leftExpression.NodeSpan().Start),
 		Token:    p.currentToken,
 		Operator: p.currentToken.Literal,
 		Left:     leftExpression,
@@ -361,59 +643,90 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 	expression := p.parseExpression(LOWEST)
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
-		panic(fmt.Sprintf("[:%d] invalid expression grouping", p.currentToken.LineNumber))
+		p.addError(ast.TokenSpan(p.currentToken), "invalid expression grouping")
 	}
 
 	return expression
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
-	expression := &ast.CallExpression{Function: function}
-	expression.Arguments = p.parseCallArguments()
+	expression := &ast.CallExpression{Token: p.currentToken, Function: function}
+	expression.Arguments = p.parseExpressionList(token.RIGHT_PARENTHESIS)
+	expression.Span = p.spanFrom(function.NodeSpan().Start)
 
 	return expression
 }
 
-func (p *Parser) parseCallArguments() []ast.Expression {
-	var args []ast.Expression
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expression := &ast.IndexExpression{Token: p.currentToken, Left: left}
 
-	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+	p.nextToken()
+	expression.Index = p.parseExpression(LOWEST)
+
+	p.expectPeek(token.RIGHT_BRACKET)
+	expression.Span = p.spanFrom(left.NodeSpan().Start)
+
+	return expression
+}
+
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{Token: p.currentToken, Object: object}
+
+	p.expectPeek(token.IDENTIFIER)
+	expression.Property = p.parseIdentifier().(*ast.Identifier)
+	expression.Span = p.spanFrom(object.NodeSpan().Start)
+
+	return expression
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.currentToken}
+	array.Elements = p.parseExpressionList(token.RIGHT_BRACKET)
+	array.Span = p.spanFrom(array.Token.Start)
+
+	return array
+}
+
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	var expressions []ast.Expression
+
+	if p.peekTokenIs(end) {
 		p.nextToken()
 
-		return args
+		return expressions
 	}
 
 	p.nextToken()
-	args = append(args, p.parseExpression(LOWEST))
+	expressions = append(expressions, p.parseExpression(LOWEST))
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
 
-		args = append(args, p.parseExpression(LOWEST))
+		expressions = append(expressions, p.parseExpression(LOWEST))
 	}
 
-	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
+	if !p.expectPeek(end) {
 		return nil
 	}
 
-	return args
+	return expressions
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	return &ast.Identifier{Span: ast.TokenSpan(p.currentToken), Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
-	return &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+	return &ast.StringLiteral{Span: ast.TokenSpan(p.currentToken), Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
-	integerLiteral := &ast.IntegerLiteral{Token: p.currentToken}
+	integerLiteral := &ast.IntegerLiteral{Span: ast.TokenSpan(p.currentToken), Token: p.currentToken}
 
 	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
 	if err != nil {
-		panic(fmt.Sprintf("[:%d] cannot parse %q as integer", p.currentToken.LineNumber, p.currentToken.Literal))
+		p.addError(ast.TokenSpan(p.currentToken), "cannot parse %q as integer", p.currentToken.Literal)
 	}
 
 	integerLiteral.Value = value
@@ -422,15 +735,7 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *Parser) parseBoolean() ast.Expression {
-	var integerBooleanValue int64
-
-	if p.currentTokenIs(token.TRUE) {
-		integerBooleanValue = 1
-	} else {
-		integerBooleanValue = 0
-	}
-
-	return &ast.IntegerLiteral{Token: p.currentToken, Value: integerBooleanValue}
+	return &ast.BooleanLiteral{Span: ast.TokenSpan(p.currentToken), Token: p.currentToken, Value: p.currentTokenIs(token.TRUE)}
 }
 
 func (p *Parser) currentTokenIs(expectedCurrentToken token.TokenType) bool {
@@ -448,14 +753,32 @@ func (p *Parser) expectPeek(expectedPeekToken token.TokenType) bool {
 		return true
 	}
 
-	panic(
-		fmt.Sprintf(
-			"[:%d] expected next token to be %s, got %s instead",
-			p.currentToken.LineNumber,
-			expectedPeekToken,
-			p.peekToken.Type,
-		),
-	)
+	p.addError(ast.TokenSpan(p.peekToken), "expected next token to be %s, got %s instead", expectedPeekToken, p.peekToken.Type)
+
+	return false
+}
+
+func (p *Parser) skipStatement() {
+	depth := 0
+
+	for !p.currentTokenIs(token.EOF) {
+		switch p.currentToken.Type {
+		case token.LEFT_BRACE:
+			depth++
+		case token.RIGHT_BRACE:
+			depth--
+		}
+
+		if depth <= 0 && (p.currentTokenIs(token.SEMICOLON) || p.currentTokenIs(token.RIGHT_BRACE)) {
+			return
+		}
+
+		if depth == 0 && p.peekTokenIs(token.RIGHT_BRACE) {
+			return
+		}
+
+		p.nextToken()
+	}
 }
 
 func (p *Parser) currentPrecedence() int {
@@ -2,6 +2,7 @@ package jsWhitespaceTranspiler
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -36,162 +37,226 @@ for (let i = 0; i < 10; i++) {
 `
 
 	expectedAst := &ast.Program{
+		Span: ast.Span{Start: position(1, 2, 1), End: position(332, 22, 2)},
 		Statements: []ast.Statement{
 			&ast.ExpressionStatement{
+				Span: ast.Span{Start: position(1, 2, 1), End: position(26, 2, 26)},
 				Expression: &ast.CallExpression{
-					Function: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "console.log", LineNumber: 2},
-						Value: "console.log",
+					Span:  ast.Span{Start: position(1, 2, 1), End: position(25, 2, 25)},
+					Token: token.Token{Type: token.LEFT_PARENTHESIS, Literal: "(", Start: position(12, 2, 12), End: position(13, 2, 13)},
+					Function: &ast.MemberExpression{
+						Span:  ast.Span{Start: position(1, 2, 1), End: position(12, 2, 12)},
+						Token: token.Token{Type: token.DOT, Literal: ".", Start: position(8, 2, 8), End: position(9, 2, 9)},
+						Object: &ast.Identifier{
+							Span:  ast.Span{Start: position(1, 2, 1), End: position(8, 2, 8)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "console", Start: position(1, 2, 1), End: position(8, 2, 8)},
+							Value: "console",
+						},
+						Property: &ast.Identifier{
+							Span:  ast.Span{Start: position(9, 2, 9), End: position(12, 2, 12)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "log", Start: position(9, 2, 9), End: position(12, 2, 12)},
+							Value: "log",
+						},
 					},
 					Arguments: []ast.Expression{
 						&ast.StringLiteral{
-							Token: token.Token{Type: token.STRING, Literal: "Hello", LineNumber: 2},
+							Span:  ast.Span{Start: position(13, 2, 13), End: position(20, 2, 20)},
+							Token: token.Token{Type: token.STRING, Literal: "Hello", Start: position(13, 2, 13), End: position(20, 2, 20)},
 							Value: "Hello",
 						},
 						&ast.IntegerLiteral{
-							Token: token.Token{Type: token.INT, Literal: "42", LineNumber: 2},
+							Span:  ast.Span{Start: position(22, 2, 22), End: position(24, 2, 24)},
+							Token: token.Token{Type: token.INT, Literal: "42", Start: position(22, 2, 22), End: position(24, 2, 24)},
 							Value: 42,
 						},
 					},
 				},
 			},
 			&ast.LetStatement{
-				Token: token.Token{Type: token.LET, Literal: "let", LineNumber: 4},
+				Span:  ast.Span{Start: position(28, 4, 1), End: position(47, 4, 20)},
+				Token: token.Token{Type: token.LET, Literal: "let", Start: position(28, 4, 1), End: position(31, 4, 4)},
 				Name: &ast.Identifier{
-					Token: token.Token{Type: token.IDENTIFIER, Literal: "text", LineNumber: 4},
+					Span:  ast.Span{Start: position(32, 4, 5), End: position(36, 4, 9)},
+					Token: token.Token{Type: token.IDENTIFIER, Literal: "text", Start: position(32, 4, 5), End: position(36, 4, 9)},
 					Value: "text",
 				},
 				Value: &ast.StringLiteral{
-					Token: token.Token{Type: token.STRING, Literal: "value", LineNumber: 4},
+					Span:  ast.Span{Start: position(39, 4, 12), End: position(46, 4, 19)},
+					Token: token.Token{Type: token.STRING, Literal: "value", Start: position(39, 4, 12), End: position(46, 4, 19)},
 					Value: "value",
 				},
 			},
 			&ast.LetStatement{
-				Token: token.Token{Type: token.LET, Literal: "let", LineNumber: 5},
+				Span:  ast.Span{Start: position(48, 5, 1), End: position(67, 5, 20)},
+				Token: token.Token{Type: token.LET, Literal: "let", Start: position(48, 5, 1), End: position(51, 5, 4)},
 				Name: &ast.Identifier{
-					Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", LineNumber: 5},
+					Span:  ast.Span{Start: position(52, 5, 5), End: position(59, 5, 12)},
+					Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", Start: position(52, 5, 5), End: position(59, 5, 12)},
 					Value: "number1",
 				},
 				Value: &ast.IntegerLiteral{
-					Token: token.Token{Type: token.INT, Literal: "1337", LineNumber: 5},
+					Span:  ast.Span{Start: position(62, 5, 15), End: position(66, 5, 19)},
+					Token: token.Token{Type: token.INT, Literal: "1337", Start: position(62, 5, 15), End: position(66, 5, 19)},
 					Value: 1337,
 				},
 			},
 			&ast.LetStatement{
-				Token: token.Token{Type: token.LET, Literal: "let", LineNumber: 6},
+				Span:  ast.Span{Start: position(68, 6, 1), End: position(90, 6, 23)},
+				Token: token.Token{Type: token.LET, Literal: "let", Start: position(68, 6, 1), End: position(71, 6, 4)},
 				Name: &ast.Identifier{
-					Token: token.Token{Type: token.IDENTIFIER, Literal: "number2", LineNumber: 6},
+					Span:  ast.Span{Start: position(72, 6, 5), End: position(79, 6, 12)},
+					Token: token.Token{Type: token.IDENTIFIER, Literal: "number2", Start: position(72, 6, 5), End: position(79, 6, 12)},
 					Value: "number2",
 				},
 				Value: &ast.Identifier{
-					Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", LineNumber: 6},
+					Span:  ast.Span{Start: position(82, 6, 15), End: position(89, 6, 22)},
+					Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", Start: position(82, 6, 15), End: position(89, 6, 22)},
 					Value: "number1",
 				},
 			},
 			&ast.ExpressionStatement{
+				Span: ast.Span{Start: position(91, 7, 1), End: position(127, 7, 37)},
 				Expression: &ast.CallExpression{
-					Function: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "console.log", LineNumber: 7},
-						Value: "console.log",
+					Span:  ast.Span{Start: position(91, 7, 1), End: position(126, 7, 36)},
+					Token: token.Token{Type: token.LEFT_PARENTHESIS, Literal: "(", Start: position(102, 7, 12), End: position(103, 7, 13)},
+					Function: &ast.MemberExpression{
+						Span:  ast.Span{Start: position(91, 7, 1), End: position(102, 7, 12)},
+						Token: token.Token{Type: token.DOT, Literal: ".", Start: position(98, 7, 8), End: position(99, 7, 9)},
+						Object: &ast.Identifier{
+							Span:  ast.Span{Start: position(91, 7, 1), End: position(98, 7, 8)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "console", Start: position(91, 7, 1), End: position(98, 7, 8)},
+							Value: "console",
+						},
+						Property: &ast.Identifier{
+							Span:  ast.Span{Start: position(99, 7, 9), End: position(102, 7, 12)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "log", Start: position(99, 7, 9), End: position(102, 7, 12)},
+							Value: "log",
+						},
 					},
 					Arguments: []ast.Expression{
 						&ast.Identifier{
-							Token: token.Token{Type: token.IDENTIFIER, Literal: "text", LineNumber: 7},
+							Span:  ast.Span{Start: position(103, 7, 13), End: position(107, 7, 17)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "text", Start: position(103, 7, 13), End: position(107, 7, 17)},
 							Value: "text",
 						},
 						&ast.Identifier{
-							Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", LineNumber: 7},
+							Span:  ast.Span{Start: position(109, 7, 19), End: position(116, 7, 26)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", Start: position(109, 7, 19), End: position(116, 7, 26)},
 							Value: "number1",
 						},
 						&ast.Identifier{
-							Token: token.Token{Type: token.IDENTIFIER, Literal: "number2", LineNumber: 7},
+							Span:  ast.Span{Start: position(118, 7, 28), End: position(125, 7, 35)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "number2", Start: position(118, 7, 28), End: position(125, 7, 35)},
 							Value: "number2",
 						},
 					},
 				},
 			},
 			&ast.LetStatement{
-				Token: token.Token{Type: token.LET, Literal: "let", LineNumber: 9},
+				Span:  ast.Span{Start: position(129, 9, 1), End: position(171, 9, 43)},
+				Token: token.Token{Type: token.LET, Literal: "let", Start: position(129, 9, 1), End: position(132, 9, 4)},
 				Name: &ast.Identifier{
-					Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 9},
+					Span:  ast.Span{Start: position(133, 9, 5), End: position(143, 9, 15)},
+					Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(133, 9, 5), End: position(143, 9, 15)},
 					Value: "expression",
 				},
 				Value: &ast.InfixExpression{
-					Token: token.Token{Type: token.GREATER_THAN, Literal: ">", LineNumber: 9},
+					Span:  ast.Span{Start: position(147, 9, 19), End: position(170, 9, 42)},
+					Token: token.Token{Type: token.GREATER_THAN, Literal: ">", Start: position(164, 9, 36), End: position(165, 9, 37)},
 					Left: &ast.InfixExpression{
-						Token: token.Token{Type: token.SLASH, Literal: "/", LineNumber: 9},
+						Span:  ast.Span{Start: position(147, 9, 19), End: position(163, 9, 35)},
+						Token: token.Token{Type: token.SLASH, Literal: "/", Start: position(160, 9, 32), End: position(161, 9, 33)},
 						Left: &ast.InfixExpression{
-							Token: token.Token{Type: token.PLUS, Literal: "+", LineNumber: 9},
+							Span:  ast.Span{Start: position(147, 9, 19), End: position(158, 9, 30)},
+							Token: token.Token{Type: token.PLUS, Literal: "+", Start: position(155, 9, 27), End: position(156, 9, 28)},
 							Left: &ast.Identifier{
-								Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", LineNumber: 9},
+								Span:  ast.Span{Start: position(147, 9, 19), End: position(154, 9, 26)},
+								Token: token.Token{Type: token.IDENTIFIER, Literal: "number1", Start: position(147, 9, 19), End: position(154, 9, 26)},
 								Value: "number1",
 							},
 							Operator: "+",
 							Right: &ast.IntegerLiteral{
-								Token: token.Token{Type: token.INT, Literal: "2", LineNumber: 9},
+								Span:  ast.Span{Start: position(157, 9, 29), End: position(158, 9, 30)},
+								Token: token.Token{Type: token.INT, Literal: "2", Start: position(157, 9, 29), End: position(158, 9, 30)},
 								Value: 2,
 							},
 						},
 						Operator: "/",
 						Right: &ast.IntegerLiteral{
-							Token: token.Token{Type: token.INT, Literal: "2", LineNumber: 9},
+							Span:  ast.Span{Start: position(162, 9, 34), End: position(163, 9, 35)},
+							Token: token.Token{Type: token.INT, Literal: "2", Start: position(162, 9, 34), End: position(163, 9, 35)},
 							Value: 2,
 						},
 					},
 					Operator: ">",
 					Right: &ast.IntegerLiteral{
-						Token: token.Token{Type: token.INT, Literal: "1000", LineNumber: 9},
+						Span:  ast.Span{Start: position(166, 9, 38), End: position(170, 9, 42)},
+						Token: token.Token{Type: token.INT, Literal: "1000", Start: position(166, 9, 38), End: position(170, 9, 42)},
 						Value: 1000,
 					},
 				},
 			},
 			&ast.ExpressionStatement{
+				Span: ast.Span{Start: position(172, 10, 1), End: position(192, 10, 21)},
 				Expression: &ast.InfixExpression{
-					Token: token.Token{Type: token.EQUALS, Literal: "===", LineNumber: 10},
+					Span:  ast.Span{Start: position(172, 10, 1), End: position(191, 10, 20)},
+					Token: token.Token{Type: token.EQUALS, Literal: "===", Start: position(183, 10, 12), End: position(186, 10, 15)},
 					Left: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 10},
+						Span:  ast.Span{Start: position(172, 10, 1), End: position(182, 10, 11)},
+						Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(172, 10, 1), End: position(182, 10, 11)},
 						Value: "expression",
 					},
 					Operator: "===",
-					Right: &ast.IntegerLiteral{
-						Token: token.Token{Type: token.TRUE, Literal: "true", LineNumber: 10},
-						Value: 1,
+					Right: &ast.BooleanLiteral{
+						Span:  ast.Span{Start: position(187, 10, 16), End: position(191, 10, 20)},
+						Token: token.Token{Type: token.TRUE, Literal: "true", Start: position(187, 10, 16), End: position(191, 10, 20)},
+						Value: true,
 					},
 				},
 			},
 			&ast.IfStatement{
-				Token: token.Token{Type: token.IF, Literal: "if", LineNumber: 12},
-				Condition: &ast.IntegerLiteral{
-					Token: token.Token{Type: token.FALSE, Literal: "false", LineNumber: 12},
-					Value: 0,
+				Span:  ast.Span{Start: position(194, 12, 1), End: position(251, 16, 2)},
+				Token: token.Token{Type: token.IF, Literal: "if", Start: position(194, 12, 1), End: position(196, 12, 3)},
+				Condition: &ast.BooleanLiteral{
+					Span:  ast.Span{Start: position(198, 12, 5), End: position(203, 12, 10)},
+					Token: token.Token{Type: token.FALSE, Literal: "false", Start: position(198, 12, 5), End: position(203, 12, 10)},
+					Value: false,
 				},
 				Consequence: &ast.BlockStatement{
-					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", LineNumber: 12},
+					Span:  ast.Span{Start: position(205, 12, 12), End: position(225, 14, 2)},
+					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", Start: position(205, 12, 12), End: position(206, 12, 13)},
 					Statements: []ast.Statement{
 						&ast.AssignmentStatement{
-							Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 13},
+							Span:  ast.Span{Start: position(208, 13, 2), End: position(223, 13, 17)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(208, 13, 2), End: position(218, 13, 12)},
 							Name: &ast.Identifier{
-								Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 13},
+								Span:  ast.Span{Start: position(208, 13, 2), End: position(218, 13, 12)},
+								Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(208, 13, 2), End: position(218, 13, 12)},
 								Value: "expression",
 							},
 							Value: &ast.IntegerLiteral{
-								Token: token.Token{Type: token.INT, Literal: "1", LineNumber: 13},
+								Span:  ast.Span{Start: position(221, 13, 15), End: position(222, 13, 16)},
+								Token: token.Token{Type: token.INT, Literal: "1", Start: position(221, 13, 15), End: position(222, 13, 16)},
 								Value: 1,
 							},
 						},
 					},
 				},
 				Alternative: &ast.BlockStatement{
-					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", LineNumber: 14},
+					Span:  ast.Span{Start: position(231, 14, 8), End: position(251, 16, 2)},
+					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", Start: position(231, 14, 8), End: position(232, 14, 9)},
 					Statements: []ast.Statement{
 						&ast.AssignmentStatement{
-							Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 15},
+							Span:  ast.Span{Start: position(234, 15, 2), End: position(249, 15, 17)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(234, 15, 2), End: position(244, 15, 12)},
 							Name: &ast.Identifier{
-								Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", LineNumber: 15},
+								Span:  ast.Span{Start: position(234, 15, 2), End: position(244, 15, 12)},
+								Token: token.Token{Type: token.IDENTIFIER, Literal: "expression", Start: position(234, 15, 2), End: position(244, 15, 12)},
 								Value: "expression",
 							},
 							Value: &ast.IntegerLiteral{
-								Token: token.Token{Type: token.INT, Literal: "2", LineNumber: 15},
+								Span:  ast.Span{Start: position(247, 15, 15), End: position(248, 15, 16)},
+								Token: token.Token{Type: token.INT, Literal: "2", Start: position(247, 15, 15), End: position(248, 15, 16)},
 								Value: 2,
 							},
 						},
@@ -199,84 +264,109 @@ for (let i = 0; i < 10; i++) {
 				},
 			},
 			&ast.ForStatement{
-				Token: token.Token{Type: token.FOR, Literal: "for", LineNumber: 18},
+				Span:  ast.Span{Start: position(253, 18, 1), End: position(332, 22, 2)},
+				Token: token.Token{Type: token.FOR, Literal: "for", Start: position(253, 18, 1), End: position(256, 18, 4)},
 				Declaration: &ast.LetStatement{
-					Token: token.Token{Type: token.LET, Literal: "let", LineNumber: 18},
+					Span:  ast.Span{Start: position(258, 18, 6), End: position(268, 18, 16)},
+					Token: token.Token{Type: token.LET, Literal: "let", Start: position(258, 18, 6), End: position(261, 18, 9)},
 					Name: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "i", LineNumber: 18},
+						Span:  ast.Span{Start: position(262, 18, 10), End: position(263, 18, 11)},
+						Token: token.Token{Type: token.IDENTIFIER, Literal: "i", Start: position(262, 18, 10), End: position(263, 18, 11)},
 						Value: "i",
 					},
 					Value: &ast.IntegerLiteral{
-						Token: token.Token{Type: token.INT, Literal: "0", LineNumber: 18},
+						Span:  ast.Span{Start: position(266, 18, 14), End: position(267, 18, 15)},
+						Token: token.Token{Type: token.INT, Literal: "0", Start: position(266, 18, 14), End: position(267, 18, 15)},
 						Value: 0,
 					},
 				},
 				Boundary: &ast.InfixExpression{
-					Token: token.Token{Type: token.LESS_THAN, Literal: "<", LineNumber: 18},
+					Span:  ast.Span{Start: position(269, 18, 17), End: position(275, 18, 23)},
+					Token: token.Token{Type: token.LESS_THAN, Literal: "<", Start: position(271, 18, 19), End: position(272, 18, 20)},
 					Left: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "i", LineNumber: 18},
+						Span:  ast.Span{Start: position(269, 18, 17), End: position(270, 18, 18)},
+						Token: token.Token{Type: token.IDENTIFIER, Literal: "i", Start: position(269, 18, 17), End: position(270, 18, 18)},
 						Value: "i",
 					},
 					Operator: "<",
 					Right: &ast.IntegerLiteral{
-						Token: token.Token{Type: token.INT, Literal: "10", LineNumber: 18},
+						Span:  ast.Span{Start: position(273, 18, 21), End: position(275, 18, 23)},
+						Token: token.Token{Type: token.INT, Literal: "10", Start: position(273, 18, 21), End: position(275, 18, 23)},
 						Value: 10,
 					},
 				},
-				Increment: &ast.SuffixExpression{
-					Token: token.Token{Type: token.INCREMENT, Literal: "++", LineNumber: 18},
-					Left: &ast.Identifier{
-						Token: token.Token{Type: token.IDENTIFIER, Literal: "i", LineNumber: 18},
-						Value: "i",
+				Increment: &ast.ExpressionStatement{
+					Span: ast.Span{Start: position(277, 18, 25), End: position(280, 18, 28)},
+					Expression: &ast.SuffixExpression{
+						Span:  ast.Span{Start: position(277, 18, 25), End: position(280, 18, 28)},
+						Token: token.Token{Type: token.INCREMENT, Literal: "++", Start: position(278, 18, 26), End: position(280, 18, 28)},
+						Left: &ast.Identifier{
+							Span:  ast.Span{Start: position(277, 18, 25), End: position(278, 18, 26)},
+							Token: token.Token{Type: token.IDENTIFIER, Literal: "i", Start: position(277, 18, 25), End: position(278, 18, 26)},
+							Value: "i",
+						},
+						Operator: "++",
 					},
-					Operator: "++",
 				},
 				Body: &ast.BlockStatement{
-					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", LineNumber: 18},
+					Span:  ast.Span{Start: position(282, 18, 30), End: position(332, 22, 2)},
+					Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", Start: position(282, 18, 30), End: position(283, 18, 31)},
 					Statements: []ast.Statement{
 						&ast.IfStatement{
-							Token: token.Token{Type: token.IF, Literal: "if", LineNumber: 19},
+							Span:  ast.Span{Start: position(286, 19, 2), End: position(330, 21, 3)},
+							Token: token.Token{Type: token.IF, Literal: "if", Start: position(286, 19, 2), End: position(288, 19, 4)},
 							Condition: &ast.InfixExpression{
-								Token: token.Token{Type: token.OR, Literal: "||", LineNumber: 19},
+								Span:  ast.Span{Start: position(290, 19, 6), End: position(312, 19, 28)},
+								Token: token.Token{Type: token.OR, Literal: "||", Start: position(302, 19, 18), End: position(304, 19, 20)},
 								Left: &ast.InfixExpression{
-									Token: token.Token{Type: token.EQUALS, Literal: "===", LineNumber: 19},
+									Span:  ast.Span{Start: position(290, 19, 6), End: position(301, 19, 17)},
+									Token: token.Token{Type: token.EQUALS, Literal: "===", Start: position(296, 19, 12), End: position(299, 19, 15)},
 									Left: &ast.InfixExpression{
-										Token: token.Token{Type: token.PERCENT, Literal: "%", LineNumber: 19},
+										Span:  ast.Span{Start: position(290, 19, 6), End: position(295, 19, 11)},
+										Token: token.Token{Type: token.PERCENT, Literal: "%", Start: position(292, 19, 8), End: position(293, 19, 9)},
 										Left: &ast.Identifier{
-											Token: token.Token{Type: token.IDENTIFIER, Literal: "i", LineNumber: 19},
+											Span:  ast.Span{Start: position(290, 19, 6), End: position(291, 19, 7)},
+											Token: token.Token{Type: token.IDENTIFIER, Literal: "i", Start: position(290, 19, 6), End: position(291, 19, 7)},
 											Value: "i",
 										},
 										Operator: "%",
 										Right: &ast.IntegerLiteral{
-											Token: token.Token{Type: token.INT, Literal: "2", LineNumber: 19},
+											Span:  ast.Span{Start: position(294, 19, 10), End: position(295, 19, 11)},
+											Token: token.Token{Type: token.INT, Literal: "2", Start: position(294, 19, 10), End: position(295, 19, 11)},
 											Value: 2,
 										},
 									},
 									Operator: "===",
 									Right: &ast.IntegerLiteral{
-										Token: token.Token{Type: token.INT, Literal: "0", LineNumber: 19},
+										Span:  ast.Span{Start: position(300, 19, 16), End: position(301, 19, 17)},
+										Token: token.Token{Type: token.INT, Literal: "0", Start: position(300, 19, 16), End: position(301, 19, 17)},
 										Value: 0,
 									},
 								},
 								Operator: "||",
 								Right: &ast.InfixExpression{
-									Token: token.Token{Type: token.EQUALS, Literal: "===", LineNumber: 19},
+									Span:  ast.Span{Start: position(305, 19, 21), End: position(312, 19, 28)},
+									Token: token.Token{Type: token.EQUALS, Literal: "===", Start: position(307, 19, 23), End: position(310, 19, 26)},
 									Left: &ast.Identifier{
-										Token: token.Token{Type: token.IDENTIFIER, Literal: "i", LineNumber: 19},
+										Span:  ast.Span{Start: position(305, 19, 21), End: position(306, 19, 22)},
+										Token: token.Token{Type: token.IDENTIFIER, Literal: "i", Start: position(305, 19, 21), End: position(306, 19, 22)},
 										Value: "i",
 									},
 									Operator: "===",
 									Right: &ast.IntegerLiteral{
-										Token: token.Token{Type: token.INT, Literal: "8", LineNumber: 19},
+										Span:  ast.Span{Start: position(311, 19, 27), End: position(312, 19, 28)},
+										Token: token.Token{Type: token.INT, Literal: "8", Start: position(311, 19, 27), End: position(312, 19, 28)},
 										Value: 8,
 									},
 								},
 							},
 							Consequence: &ast.BlockStatement{
-								Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", LineNumber: 19},
+								Span:  ast.Span{Start: position(314, 19, 30), End: position(330, 21, 3)},
+								Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", Start: position(314, 19, 30), End: position(315, 19, 31)},
 								Statements: []ast.Statement{
 									&ast.ContinueStatement{
-										Token: token.Token{Type: token.CONTINUE, Literal: "continue", LineNumber: 20},
+										Span:  ast.Span{Start: position(318, 20, 3), End: position(327, 20, 12)},
+										Token: token.Token{Type: token.CONTINUE, Literal: "continue", Start: position(318, 20, 3), End: position(326, 20, 11)},
 									},
 								},
 							},
@@ -291,7 +381,10 @@ for (let i = 0; i < 10; i++) {
 	lexer := NewLexer(strings.NewReader(input))
 	parser := NewParser(lexer)
 
-	parsedAst := parser.ParseProgram()
+	parsedAst, err := parser.ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
 
 	if !reflect.DeepEqual(parsedAst, expectedAst) {
 		expectedAstJson, _ := json.MarshalIndent(expectedAst, "", "  ")
@@ -300,3 +393,325 @@ for (let i = 0; i < 10; i++) {
 		t.Fatalf("invalid ast. expected=%s, got=%s", expectedAstJson, parsedAstJson)
 	}
 }
+
+func TestParserFunctionDeclaration(t *testing.T) {
+	input := `function add(a, b) {
+	return a + b;
+}
+function log() {
+	return;
+}`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("statement count incorrect. expected=2, got=%d", len(program.Statements))
+	}
+
+	add, ok := program.Statements[0].(*ast.FunctionDeclaration)
+	if !ok {
+		t.Fatalf("statement type incorrect. expected=*ast.FunctionDeclaration, got=%T", program.Statements[0])
+	}
+
+	if add.Name.Value != "add" || len(add.Parameters) != 2 || add.Parameters[0].Value != "a" || add.Parameters[1].Value != "b" {
+		t.Errorf("function signature incorrect. got=%s(%v)", add.Name.Value, add.Parameters)
+	}
+
+	returnStatement, ok := add.Body.Statements[0].(*ast.ReturnStatement)
+	if !ok {
+		t.Fatalf("statement type incorrect. expected=*ast.ReturnStatement, got=%T", add.Body.Statements[0])
+	}
+
+	if _, ok = returnStatement.Value.(*ast.InfixExpression); !ok {
+		t.Errorf("return value type incorrect. expected=*ast.InfixExpression, got=%T", returnStatement.Value)
+	}
+
+	log := program.Statements[1].(*ast.FunctionDeclaration)
+
+	if len(log.Parameters) != 0 || log.Body.Statements[0].(*ast.ReturnStatement).Value != nil {
+		t.Errorf("function without parameters and return value parsed incorrectly")
+	}
+}
+
+func TestParserWhileStatements(t *testing.T) {
+	input := `while (i < 10) {
+	i++;
+}
+do {
+	break;
+} while (i > 0);`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("statement count incorrect. expected=2, got=%d", len(program.Statements))
+	}
+
+	whileStatement, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("statement type incorrect. expected=*ast.WhileStatement, got=%T", program.Statements[0])
+	}
+
+	if condition, ok := whileStatement.Condition.(*ast.InfixExpression); !ok || condition.Operator != ast.LESS_THAN {
+		t.Errorf("while condition incorrect. got=%+v", whileStatement.Condition)
+	}
+
+	if len(whileStatement.Body.Statements) != 1 {
+		t.Errorf("while body statement count incorrect. expected=1, got=%d", len(whileStatement.Body.Statements))
+	}
+
+	doWhileStatement, ok := program.Statements[1].(*ast.DoWhileStatement)
+	if !ok {
+		t.Fatalf("statement type incorrect. expected=*ast.DoWhileStatement, got=%T", program.Statements[1])
+	}
+
+	if _, ok = doWhileStatement.Body.Statements[0].(*ast.BreakStatement); !ok {
+		t.Errorf("do-while body incorrect. got=%T", doWhileStatement.Body.Statements[0])
+	}
+
+	if condition, ok := doWhileStatement.Condition.(*ast.InfixExpression); !ok || condition.Operator != ast.GREATER_THAN {
+		t.Errorf("do-while condition incorrect. got=%+v", doWhileStatement.Condition)
+	}
+
+	if doWhileStatement.Span.End != position(54, 6, 17) {
+		t.Errorf("do-while span end incorrect. got=%+v", doWhileStatement.Span.End)
+	}
+}
+
+func TestParserLabeledStatements(t *testing.T) {
+	input := `outer: inner: while (true) {
+	break outer;
+	continue inner;
+}`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	outer, ok := program.Statements[0].(*ast.LabeledStatement)
+	if !ok || outer.Label.Value != "outer" {
+		t.Fatalf("outer labeled statement incorrect. got=%+v", program.Statements[0])
+	}
+
+	inner, ok := outer.Body.(*ast.LabeledStatement)
+	if !ok || inner.Label.Value != "inner" {
+		t.Fatalf("inner labeled statement incorrect. got=%+v", outer.Body)
+	}
+
+	loop, ok := inner.Body.(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("labeled statement body type incorrect. expected=*ast.WhileStatement, got=%T", inner.Body)
+	}
+
+	if breakStatement, ok := loop.Body.Statements[0].(*ast.BreakStatement); !ok || breakStatement.Label.Value != "outer" {
+		t.Errorf("break statement incorrect. got=%+v", loop.Body.Statements[0])
+	}
+
+	if continueStatement, ok := loop.Body.Statements[1].(*ast.ContinueStatement); !ok || continueStatement.Label.Value != "inner" {
+		t.Errorf("continue statement incorrect. got=%+v", loop.Body.Statements[1])
+	}
+}
+
+func TestParserArrays(t *testing.T) {
+	input := `let a = [1, "two", []];
+a[a.length - 1] = a[0];
+a.push(-a[1][2]);`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	literal, ok := program.Statements[0].(*ast.LetStatement).Value.(*ast.ArrayLiteral)
+	if !ok || len(literal.Elements) != 3 {
+		t.Fatalf("array literal incorrect. got=%+v", program.Statements[0].(*ast.LetStatement).Value)
+	}
+
+	if nested, ok := literal.Elements[2].(*ast.ArrayLiteral); !ok || len(nested.Elements) != 0 {
+		t.Errorf("nested array literal incorrect. got=%+v", literal.Elements[2])
+	}
+
+	assignment, ok := program.Statements[1].(*ast.IndexAssignmentStatement)
+	if !ok {
+		t.Fatalf("statement type incorrect. expected=*ast.IndexAssignmentStatement, got=%T", program.Statements[1])
+	}
+
+	index, ok := assignment.Target.Index.(*ast.InfixExpression)
+	if !ok || index.Operator != ast.SUBTRACTION {
+		t.Fatalf("index expression incorrect. got=%+v", assignment.Target.Index)
+	}
+
+	if member, ok := index.Left.(*ast.MemberExpression); !ok || member.Property.Value != "length" {
+		t.Errorf("member expression incorrect. got=%+v", index.Left)
+	}
+
+	if _, ok = assignment.Value.(*ast.IndexExpression); !ok {
+		t.Errorf("assigned value type incorrect. expected=*ast.IndexExpression, got=%T", assignment.Value)
+	}
+
+	call := program.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+
+	if member, ok := call.Function.(*ast.MemberExpression); !ok || member.Property.Value != "push" {
+		t.Errorf("called member incorrect. got=%+v", call.Function)
+	}
+
+	negation, ok := call.Arguments[0].(*ast.PrefixExpression)
+	if !ok {
+		t.Fatalf("argument type incorrect. expected=*ast.PrefixExpression, got=%T", call.Arguments[0])
+	}
+
+	outer, ok := negation.Right.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("negated expression type incorrect. expected=*ast.IndexExpression, got=%T", negation.Right)
+	}
+
+	if _, ok = outer.Left.(*ast.IndexExpression); !ok {
+		t.Errorf("chained index expression incorrect. got=%T", outer.Left)
+	}
+}
+
+func TestParserConditionalExpressions(t *testing.T) {
+	input := `let a = b > 1 || c ? d + 1 : e ? f : g;`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	conditional, ok := program.Statements[0].(*ast.LetStatement).Value.(*ast.ConditionalExpression)
+	if !ok {
+		t.Fatalf("expression type incorrect. expected=*ast.ConditionalExpression, got=%T", program.Statements[0].(*ast.LetStatement).Value)
+	}
+
+	if condition, ok := conditional.Condition.(*ast.InfixExpression); !ok || condition.Operator != ast.OR {
+		t.Errorf("condition incorrect. got=%+v", conditional.Condition)
+	}
+
+	if consequence, ok := conditional.Consequence.(*ast.InfixExpression); !ok || consequence.Operator != ast.ADDITION {
+		t.Errorf("consequence incorrect. got=%+v", conditional.Consequence)
+	}
+
+	alternative, ok := conditional.Alternative.(*ast.ConditionalExpression)
+	if !ok {
+		t.Fatalf("alternative type incorrect. expected=*ast.ConditionalExpression, got=%T", conditional.Alternative)
+	}
+
+	if condition, ok := alternative.Condition.(*ast.Identifier); !ok || condition.Value != "e" {
+		t.Errorf("nested condition incorrect. got=%+v", alternative.Condition)
+	}
+
+	expectedSpan := ast.Span{Start: position(8, 1, 9), End: position(38, 1, 39)}
+	if conditional.Span != expectedSpan {
+		t.Errorf("span incorrect. expected=%+v, got=%+v", expectedSpan, conditional.Span)
+	}
+}
+
+func TestParserLogicalPrecedence(t *testing.T) {
+	input := `a || b && c || d;`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	outer, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InfixExpression)
+	if !ok || outer.Operator != ast.OR {
+		t.Fatalf("outer expression incorrect. got=%+v", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+
+	left, ok := outer.Left.(*ast.InfixExpression)
+	if !ok || left.Operator != ast.OR {
+		t.Fatalf("left expression incorrect. got=%+v", outer.Left)
+	}
+
+	if and, ok := left.Right.(*ast.InfixExpression); !ok || and.Operator != ast.AND {
+		t.Errorf("&& should bind tighter than ||. got=%+v", left.Right)
+	}
+}
+
+func TestParserUpdateStatements(t *testing.T) {
+	input := `a += 1;
+b[0] %= c * 2;
+++a;
+for (let i = 0; i < 10; i -= -2) {}`
+
+	program, err := NewParser(NewLexer(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	tests := []struct {
+		statement        ast.Statement
+		expectedTarget   string
+		expectedOperator string
+	}{
+		{program.Statements[0], "*ast.Identifier", ast.ADDITION},
+		{program.Statements[1], "*ast.IndexExpression", ast.MODULO},
+		{program.Statements[3].(*ast.ForStatement).Increment, "*ast.Identifier", ast.SUBTRACTION},
+	}
+
+	for i, test := range tests {
+		statement, ok := test.statement.(*ast.CompoundAssignmentStatement)
+		if !ok {
+			t.Fatalf("statement (#%d) type incorrect. expected=*ast.CompoundAssignmentStatement, got=%T", i+1, test.statement)
+		}
+
+		if target := fmt.Sprintf("%T", statement.Target); target != test.expectedTarget {
+			t.Errorf("target (#%d) type incorrect. expected=%s, got=%s", i+1, test.expectedTarget, target)
+		}
+
+		if statement.Operator != test.expectedOperator {
+			t.Errorf("operator (#%d) incorrect. expected=%s, got=%s", i+1, test.expectedOperator, statement.Operator)
+		}
+	}
+
+	prefix, ok := program.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.PrefixExpression)
+	if !ok || prefix.Operator != ast.INCREMENT {
+		t.Errorf("prefix expression incorrect. got=%+v", program.Statements[2])
+	}
+}
+
+func position(offset, line, column int) token.Position {
+	return token.Position{Offset: offset, Line: line, Column: column}
+}
+
+func TestParserErrors(t *testing.T) {
+	input := `let = 5;
+let valid = 1;
+console.log((valid + 1;
+if (valid) {
+	let = 2;
+}
+// comment
+let text = "unterminated`
+
+	expectedErrors := Diagnostics{
+		{File: "test.js", Line: 1, Column: 5, Offset: 4, Length: 1, Severity: ERROR, Message: "expected next token to be IDENTIFIER, got = instead"},
+		{File: "test.js", Line: 3, Column: 23, Offset: 46, Length: 1, Severity: ERROR, Message: "expected next token to be ), got ; instead"},
+		{File: "test.js", Line: 5, Column: 6, Offset: 66, Length: 1, Severity: ERROR, Message: "expected next token to be IDENTIFIER, got = instead"},
+		{File: "test.js", Line: 7, Column: 1, Offset: 73, Length: 10, Severity: ERROR, Message: "comments are a violation of DRY"},
+		{File: "test.js", Line: 8, Column: 12, Offset: 95, Length: 13, Severity: ERROR, Message: "unterminated string literal"},
+		{File: "test.js", Line: 8, Column: 12, Offset: 95, Length: 13, Severity: ERROR, Message: "missing semicolon at the end of assignment statement"},
+	}
+
+	_, err := NewParser(NewNamedLexer(strings.NewReader(input), "test.js")).ParseProgram()
+	if err == nil {
+		t.Fatalf("expected parsing errors")
+	}
+
+	errors, ok := err.(Diagnostics)
+	if !ok {
+		t.Fatalf("error type incorrect. expected=Diagnostics, got=%T", err)
+	}
+
+	if !reflect.DeepEqual(errors, expectedErrors) {
+		t.Fatalf("errors incorrect. expected=\n%s\ngot=\n%s", expectedErrors, errors)
+	}
+}
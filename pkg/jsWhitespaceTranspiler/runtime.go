@@ -0,0 +1,427 @@
+package jsWhitespaceTranspiler
+
+import (
+	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/object"
+	"github.com/pakut2/w-format/pkg/whitespace"
+)
+
+// Heap blocks created at runtime are handed out by a bump allocator and never freed.
+// They start far above the global variables, leaving the addresses in between to call frames.
+const dynamicHeapStartAddress = 1 << 20
+
+const (
+	PRINT_STRING_SUBROUTINE          = "PRINT_STRING"
+	CONCAT_STRINGS_SUBROUTINE        = "CONCAT_STRINGS"
+	COPY_WORDS_SUBROUTINE            = "COPY_WORDS"
+	ARRAY_ELEMENT_ADDRESS_SUBROUTINE = "ARRAY_ELEMENT_ADDRESS"
+	ARRAY_PUSH_SUBROUTINE            = "ARRAY_PUSH"
+	READ_LINE_SUBROUTINE             = "READ_LINE"
+)
+
+var runtimeSubroutines = map[string]func(t *Transpiler){
+	PRINT_STRING_SUBROUTINE:          (*Transpiler).printStringSubroutine,
+	CONCAT_STRINGS_SUBROUTINE:        (*Transpiler).concatStringsSubroutine,
+	COPY_WORDS_SUBROUTINE:            (*Transpiler).copyWordsSubroutine,
+	ARRAY_ELEMENT_ADDRESS_SUBROUTINE: (*Transpiler).arrayElementAddressSubroutine,
+	ARRAY_PUSH_SUBROUTINE:            (*Transpiler).arrayPushSubroutine,
+	READ_LINE_SUBROUTINE:             (*Transpiler).readLineSubroutine,
+}
+
+// Offsets of the fields of an array header block, the length is stored at the address of the header itself
+const (
+	arrayCapacityHeaderOffset = 1
+	arrayElementsHeaderOffset = 2
+	arrayHeaderSize           = 3
+
+	minimumArrayCapacity = 4
+)
+
+// Stack: size -> heap address of a new block
+func (t *Transpiler) allocateInstruction() {
+	heapPointerHeapAddress := t.getHeapPointerHeapAddress()
+
+	t.retrieveFromHeapInstruction(heapPointerHeapAddress)
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.Add())
+	t.pushNumberLiteralToStackInstruction(heapPointerHeapAddress)
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+}
+
+// String blocks hold the length of the string followed by its chars
+type stringConstant struct {
+	value       []rune
+	heapAddress int64
+}
+
+// getStaticHeapBlock reserves consecutive addresses among the global variables, also while transpiling a function
+func (t *Transpiler) getStaticHeapBlock(size int64) int64 {
+	heapAddress := t.currentHeapAddress + 1
+	t.currentHeapAddress += size
+
+	return heapAddress
+}
+
+func (t *Transpiler) getHeapPointerHeapAddress() int64 {
+	if t.heapPointerHeapAddress == 0 {
+		t.heapPointerHeapAddress = t.getStaticHeapBlock(1)
+	}
+
+	return t.heapPointerHeapAddress
+}
+
+func (t *Transpiler) getStringConstantHeapAddress(value string) int64 {
+	if heapAddress, ok := t.stringConstantHeapAddresses[value]; ok {
+		return heapAddress
+	}
+
+	chars := []rune(value)
+	heapAddress := t.getStaticHeapBlock(int64(len(chars)) + 1)
+
+	t.stringConstants = append(t.stringConstants, stringConstant{value: chars, heapAddress: heapAddress})
+	t.stringConstantHeapAddresses[value] = heapAddress
+
+	return heapAddress
+}
+
+func (t *Transpiler) callRuntimeSubroutine(name string) {
+	labelId, ok := t.runtimeSubroutineLabelIds[name]
+	if !ok {
+		labelId = t.getEmptyLabelId()
+
+		t.runtimeSubroutineLabelIds[name] = labelId
+		t.runtimeSubroutineNames = append(t.runtimeSubroutineNames, name)
+	}
+
+	t.addInstruction(whitespace.CallSubroutine(labelId))
+}
+
+func (t *Transpiler) transpileRuntimeSubroutines() {
+	// Subroutines can call other subroutines, adding them to the list while it is being transpiled
+	for i := 0; i < len(t.runtimeSubroutineNames); i++ {
+		name := t.runtimeSubroutineNames[i]
+
+		t.addInstruction(whitespace.Label(t.runtimeSubroutineLabelIds[name]))
+		runtimeSubroutines[name](t)
+	}
+}
+
+// prependPrologue initializes the frame pointer, the allocator and string constants before the program starts
+func (t *Transpiler) prependPrologue() {
+	body := t.instructions
+	t.instructions = nil
+
+	if len(t.functions) > 0 {
+		// Call frames are placed right after the heap addresses of global variables
+		t.storeValueInHeapInstruction(framePointerHeapAddress, t.currentHeapAddress+1)
+	}
+
+	if t.heapPointerHeapAddress != 0 {
+		t.storeValueInHeapInstruction(t.heapPointerHeapAddress, dynamicHeapStartAddress)
+	}
+
+	for _, constant := range t.stringConstants {
+		t.storeValueInHeapInstruction(constant.heapAddress, int64(len(constant.value)))
+
+		for i, char := range constant.value {
+			t.storeValueInHeapInstruction(constant.heapAddress+int64(i)+1, int64(char))
+		}
+	}
+
+	t.instructions = append(t.instructions, body...)
+}
+
+// pushStringAddressInstruction pushes the address of constant strings, other strings are already on the stack
+func (t *Transpiler) pushStringAddressInstruction(value *object.String) {
+	if value.Constant {
+		t.pushNumberLiteralToStackInstruction(t.getStringConstantHeapAddress(value.Value))
+	}
+}
+
+// Stack: string -> (empty), constant strings are printed char by char instead
+func (t *Transpiler) printStringInstruction(value *object.String) {
+	if value.Constant {
+		for _, char := range value.Value {
+			t.pushNumberLiteralToStackInstruction(int64(char))
+			t.printTopStackCharInstruction()
+		}
+
+		return
+	}
+
+	t.callRuntimeSubroutine(PRINT_STRING_SUBROUTINE)
+}
+
+// Stack: left, right -> result, constant operands are not on the stack
+func (t *Transpiler) concatStringsInstruction(left, right *object.String) *object.String {
+	if left.Constant && right.Constant {
+		return &object.String{Constant: true, Value: left.Value + right.Value}
+	}
+
+	t.pushStringAddressInstruction(left)
+	if left.Constant {
+		t.addInstruction(whitespace.SwapTwoTopStackItems())
+	}
+
+	t.pushStringAddressInstruction(right)
+	t.callRuntimeSubroutine(CONCAT_STRINGS_SUBROUTINE)
+
+	return &object.String{}
+}
+
+// Stack: string -> (empty)
+func (t *Transpiler) printStringSubroutine() {
+	loopLabel := t.getEmptyLabelId()
+	endLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.pushNumberLiteralToStackInstruction(0)
+
+	// Stack: string, length, index
+	t.addInstruction(whitespace.Label(loopLabel))
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.Subtract())
+	t.addInstruction(whitespace.JumpToLabelIfZero(endLabel))
+
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.printTopStackCharInstruction()
+	t.addInstruction(whitespace.JumpToLabel(loopLabel))
+
+	t.addInstruction(whitespace.Label(endLabel))
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.EndSubroutine())
+}
+
+// Stack: left string, right string -> concatenated string
+func (t *Transpiler) concatStringsSubroutine() {
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Add())
+
+	// Stack: left, right, length
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.allocateInstruction()
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+
+	// Stack: left, right, result
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(4))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.callRuntimeSubroutine(COPY_WORDS_SUBROUTINE)
+
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(4))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(3))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.callRuntimeSubroutine(COPY_WORDS_SUBROUTINE)
+
+	t.addInstruction(whitespace.SlideStackItems(2))
+	t.addInstruction(whitespace.EndSubroutine())
+}
+
+// Stack: source heap address, destination heap address, count -> (empty)
+func (t *Transpiler) copyWordsSubroutine() {
+	loopLabel := t.getEmptyLabelId()
+	endLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.Label(loopLabel))
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.JumpToLabelIfZero(endLabel))
+
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Subtract())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(3))
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.StoreInHeap())
+	t.addInstruction(whitespace.JumpToLabel(loopLabel))
+
+	t.addInstruction(whitespace.Label(endLabel))
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.EndSubroutine())
+}
+
+// Stack: array, index -> heap address of the element
+func (t *Transpiler) arrayElementAddressSubroutine() {
+	outOfBoundsLabel := t.getEmptyLabelId()
+	inBoundsLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.JumpToLabelIfNegative(outOfBoundsLabel))
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Subtract())
+	t.addInstruction(whitespace.JumpToLabelIfNegative(inBoundsLabel))
+
+	t.addInstruction(whitespace.Label(outOfBoundsLabel))
+	t.printStringInstruction(&object.String{Constant: true, Value: "RangeError: array index out of bounds\n"})
+	t.addInstruction(whitespace.EndProgram())
+
+	t.addInstruction(whitespace.Label(inBoundsLabel))
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.pushNumberLiteralToStackInstruction(arrayElementsHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.EndSubroutine())
+}
+
+// Stack: array, element -> length of the array
+func (t *Transpiler) arrayPushSubroutine() {
+	storeLabel := t.getEmptyLabelId()
+	growLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.pushNumberLiteralToStackInstruction(arrayCapacityHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Subtract())
+	t.addInstruction(whitespace.JumpToLabelIfZero(growLabel))
+
+	// Stack: array, element
+	t.addInstruction(whitespace.Label(storeLabel))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(arrayElementsHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.StoreInHeap())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.EndSubroutine())
+
+	// Elements are moved to a new block of twice the capacity
+	t.addInstruction(whitespace.Label(growLabel))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(arrayCapacityHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.pushNumberLiteralToStackInstruction(2)
+	t.addInstruction(whitespace.Multiply())
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.allocateInstruction()
+
+	// Stack: array, element, capacity, elements
+	t.addInstruction(whitespace.LiftStackItem(3))
+	t.pushNumberLiteralToStackInstruction(arrayElementsHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.LiftStackItem(5))
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.callRuntimeSubroutine(COPY_WORDS_SUBROUTINE)
+
+	t.addInstruction(whitespace.LiftStackItem(3))
+	t.pushNumberLiteralToStackInstruction(arrayElementsHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.pushNumberLiteralToStackInstruction(arrayCapacityHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+	t.addInstruction(whitespace.JumpToLabel(storeLabel))
+}
+
+// Stack: (empty) -> string read from the input up to a line feed or the end of the input.
+// The chars are read straight into the free heap, nothing else is allocated until the block is complete.
+func (t *Transpiler) readLineSubroutine() {
+	loopLabel := t.getEmptyLabelId()
+	endOfInputLabel := t.getEmptyLabelId()
+	endLabel := t.getEmptyLabelId()
+
+	heapPointerHeapAddress := t.getHeapPointerHeapAddress()
+
+	t.retrieveFromHeapInstruction(heapPointerHeapAddress)
+	t.pushNumberLiteralToStackInstruction(0)
+
+	// Stack: block, length
+	t.addInstruction(whitespace.Label(loopLabel))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.Add())
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.ReadCharToHeap())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+
+	// The end of the input is read as -1
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.JumpToLabelIfNegative(endOfInputLabel))
+	t.pushNumberLiteralToStackInstruction('\n')
+	t.addInstruction(whitespace.Subtract())
+	t.addInstruction(whitespace.JumpToLabelIfZero(endLabel))
+
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.JumpToLabel(loopLabel))
+
+	t.addInstruction(whitespace.Label(endOfInputLabel))
+	t.addInstruction(whitespace.DiscardTopStackItem())
+
+	t.addInstruction(whitespace.Label(endLabel))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.pushNumberLiteralToStackInstruction(heapPointerHeapAddress)
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.LiftStackItem(2))
+	t.addInstruction(whitespace.Add())
+	t.pushNumberLiteralToStackInstruction(1)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	t.addInstruction(whitespace.EndSubroutine())
+}
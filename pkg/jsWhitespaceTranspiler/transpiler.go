@@ -2,34 +2,83 @@ package jsWhitespaceTranspiler
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/ast"
 	"github.com/pakut2/w-format/pkg/jsWhitespaceTranspiler/internal/object"
 	"github.com/pakut2/w-format/pkg/whitespace"
 )
 
+// Heap address holding the base address of the current function call frame
+const framePointerHeapAddress = 0
+
 type Transpiler struct {
 	instructions []whitespace.Instruction
+	fileName     string
+	errors       Diagnostics
 
 	currentHeapAddress int64
 	currentLabelId     int64
+	frame              *frame
+
+	// Heap addresses of variables that are no longer used, reused by the next declared ones
+	freeHeapAddresses []int64
+	// Global variables mentioned in functions stay alive for the whole program
+	functionMentions map[string]bool
+	largestFrameSize int64
+
+	heapPointerHeapAddress      int64
+	stringConstants             []stringConstant
+	stringConstantHeapAddresses map[string]int64
+	runtimeSubroutineLabelIds   map[string]int64
+	runtimeSubroutineNames      []string
 
 	environment      *object.Environment
 	builtInFunctions map[string]*object.BuiltIn
+	functions        []*object.Function
+
+	// Functions of the previous transpilation, whose parameter and return types are known from the start
+	signatures map[string]*object.Function
+	// Results of calls to functions whose return type is not known yet, taken for numbers
+	guessedResults map[object.Object]bool
+}
+
+// Slots of a frame are addressed relative to the frame pointer, so every call gets its own copy of them.
+// They are represented by negative heap addresses, -n being the n-th slot of the frame. Slot 0 holds the frame pointer of the caller.
+type frame struct {
+	function  *object.Function
+	size      int64
+	freeSlots []int64
+
+	sizeInstructionIndexes []int
 }
 
 func NewTranspiler() *Transpiler {
-	t := &Transpiler{
-		currentHeapAddress: 0,
-		environment:        object.NewEnvironment(),
-	}
+	t := &Transpiler{}
+	t.reset()
 
 	t.builtInFunctions = make(map[string]*object.BuiltIn)
 	t.registerBuildInFunction("console.log", &object.BuiltIn{Function: t.consoleLogBuiltInFunction})
+	t.registerBuildInFunction("readline", &object.BuiltIn{Function: t.readlineBuiltInFunction})
+	t.registerBuildInFunction("readInt", &object.BuiltIn{Function: t.readIntBuiltInFunction})
 
 	return t
 }
 
+// reset clears the state of a transpilation, keeping the built-in functions and the known function signatures
+func (t *Transpiler) reset() {
+	*t = Transpiler{
+		currentHeapAddress:          0,
+		stringConstantHeapAddresses: make(map[string]int64),
+		runtimeSubroutineLabelIds:   make(map[string]int64),
+		functionMentions:            make(map[string]bool),
+		guessedResults:              make(map[object.Object]bool),
+		environment:                 object.NewEnvironment(),
+		builtInFunctions:            t.builtInFunctions,
+		signatures:                  t.signatures,
+	}
+}
+
 func (t *Transpiler) registerBuildInFunction(functionName string, function *object.BuiltIn) {
 	t.builtInFunctions[functionName] = function
 }
@@ -38,142 +87,498 @@ func (t *Transpiler) addInstruction(instruction whitespace.Instruction) {
 	t.instructions = append(t.instructions, instruction)
 }
 
-func (t *Transpiler) consoleLogBuiltInFunction(args ...object.Object) object.Object {
+func (t *Transpiler) addError(node ast.Node, format string, args ...any) {
+	t.errors = append(t.errors, newDiagnostic(t.fileName, node.NodeSpan(), ERROR, format, args...))
+
+	panic(bailout{})
+}
+
+// consoleLogBuiltInFunction prints the arguments left on the stack in order, copying each from its depth before printing it
+func (t *Transpiler) consoleLogBuiltInFunction(args ...object.Object) (object.Object, error) {
+	stackedArgs := 0
+
+	for _, arg := range args {
+		if onStack(arg) {
+			stackedArgs++
+		}
+	}
+
+	printedStackedArgs := 0
+
 	for i, arg := range args {
-		switch arg := arg.(type) {
-		case *object.String:
-			for _, char := range arg.Chars {
-				t.retrieveFromHeapInstruction(char.HeapAddress)
-				t.printTopStackCharInstruction()
-			}
+		if onStack(arg) {
+			printedStackedArgs++
 
-			if i != len(args)-1 {
-				t.pushNumberLiteralToStackInstruction(' ')
-				t.printTopStackCharInstruction()
+			// The last argument on the stack is printed directly, the ones below it are left to be discarded
+			if printedStackedArgs < stackedArgs {
+				t.addInstruction(whitespace.LiftStackItem(stackedArgs - printedStackedArgs))
 			}
+		}
+
+		switch arg := arg.(type) {
+		case *object.String:
+			t.printStringInstruction(arg)
 		case *object.Integer:
-			t.retrieveFromHeapInstruction(arg.HeapAddress)
 			t.printTopStackIntegerInstruction()
-
-			if i != len(args)-1 {
-				t.pushNumberLiteralToStackInstruction(' ')
-				t.printTopStackCharInstruction()
-			}
+		case *object.Boolean:
+			t.printBooleanInstruction()
 		default:
-			panic(fmt.Sprintf("argument %s not supported", arg.Type()))
+			return nil, fmt.Errorf("argument %s not supported", arg.Type())
 		}
+
+		if i != len(args)-1 {
+			t.pushNumberLiteralToStackInstruction(' ')
+			t.printTopStackCharInstruction()
+		}
+	}
+
+	for range stackedArgs - 1 {
+		t.addInstruction(whitespace.DiscardTopStackItem())
 	}
 
 	t.pushNumberLiteralToStackInstruction('\n')
 	t.printTopStackCharInstruction()
 
-	return &object.Void{}
+	return &object.Void{}, nil
+}
+
+// readlineBuiltInFunction reads a line from the input without the line feed, an empty string once the input ends
+func (t *Transpiler) readlineBuiltInFunction(args ...object.Object) (object.Object, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("readline expects 0 argument(s), got %d", len(args))
+	}
+
+	t.callRuntimeSubroutine(READ_LINE_SUBROUTINE)
+
+	return &object.String{}, nil
+}
+
+// readIntBuiltInFunction reads a whole line from the input as a number, the number is read into the free heap
+func (t *Transpiler) readIntBuiltInFunction(args ...object.Object) (object.Object, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("readInt expects 0 argument(s), got %d", len(args))
+	}
+
+	t.retrieveFromHeapInstruction(t.getHeapPointerHeapAddress())
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.addInstruction(whitespace.ReadIntegerToHeap())
+	t.addInstruction(whitespace.RetrieveFromHeap())
+
+	return &object.Integer{}, nil
+}
+
+// Stack: boolean -> (empty)
+func (t *Transpiler) printBooleanInstruction() {
+	falseLabel := t.getEmptyLabelId()
+	endPrintLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.JumpToLabelIfZero(falseLabel))
+
+	t.printStringInstruction(&object.String{Constant: true, Value: "true"})
+	t.addInstruction(whitespace.JumpToLabel(endPrintLabel))
+
+	t.addInstruction(whitespace.Label(falseLabel))
+	t.printStringInstruction(&object.String{Constant: true, Value: "false"})
+
+	t.addInstruction(whitespace.Label(endPrintLabel))
 }
 
 func (t *Transpiler) getEmptyHeapAddress() int64 {
+	if t.frame != nil {
+		if len(t.frame.freeSlots) > 0 {
+			return popHeapAddress(&t.frame.freeSlots)
+		}
+
+		t.frame.size++
+
+		return -t.frame.size
+	}
+
+	if len(t.freeHeapAddresses) > 0 {
+		return popHeapAddress(&t.freeHeapAddresses)
+	}
+
 	t.currentHeapAddress++
 
 	return t.currentHeapAddress
 }
 
+func popHeapAddress(heapAddresses *[]int64) int64 {
+	heapAddress := (*heapAddresses)[len(*heapAddresses)-1]
+	*heapAddresses = (*heapAddresses)[:len(*heapAddresses)-1]
+
+	return heapAddress
+}
+
+// releaseVariable hands the heap address of a variable over to the variables declared after it
+func (t *Transpiler) releaseVariable(name string) {
+	variable, ok := t.environment.GetLocal(name)
+	if !ok || !onStack(variable) {
+		return
+	}
+
+	if t.frame != nil {
+		t.frame.freeSlots = append(t.frame.freeSlots, heapAddress(variable))
+
+		return
+	}
+
+	if !t.functionMentions[name] {
+		t.freeHeapAddresses = append(t.freeHeapAddresses, heapAddress(variable))
+	}
+}
+
 func (t *Transpiler) getEmptyLabelId() int64 {
 	t.currentLabelId++
 
 	return t.currentLabelId
 }
 
-func (t *Transpiler) TranspileProgram(program *ast.Program) object.Object {
+// TranspileProgram transpiles the program again as long as it learns new parameter or return types of functions,
+// since calls can be transpiled before the functions they call
+func (t *Transpiler) TranspileProgram(program *ast.Program) (object.Object, error) {
+	for {
+		result, err := t.transpileProgram(program)
+		if !t.learnedSignatures() {
+			return result, err
+		}
+
+		t.signatures = make(map[string]*object.Function, len(t.functions))
+		for _, function := range t.functions {
+			t.signatures[function.Declaration.Name.Value] = function
+		}
+
+		t.reset()
+	}
+}
+
+// learnedSignatures reports whether the last transpilation found types its functions did not know from the start
+func (t *Transpiler) learnedSignatures() bool {
+	for _, function := range t.functions {
+		known, ok := t.signatures[function.Declaration.Name.Value]
+		if !ok {
+			known = &object.Function{ParameterTypes: make([]object.ObjectType, len(function.ParameterTypes))}
+		}
+
+		if function.ReturnType != known.ReturnType || !slices.Equal(function.ParameterTypes, known.ParameterTypes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Transpiler) transpileProgram(program *ast.Program) (object.Object, error) {
+	t.fileName = program.FileName
+
+	t.declareFunctions(program.Statements)
+
+	var statements []ast.Statement
+
 	for _, statement := range program.Statements {
-		t.transpile(statement, nil)
+		if function, ok := statement.(*ast.FunctionDeclaration); ok {
+			mentionedNames(function, t.functionMentions)
+
+			continue
+		}
+
+		statements = append(statements, statement)
 	}
 
+	t.transpileStatements(statements, nil)
+
 	t.addInstruction(whitespace.EndProgram())
 
+	for _, function := range t.functions {
+		t.transpileFunction(function)
+	}
+
+	t.transpileRuntimeSubroutines()
+
+	if t.errors.HasErrors() {
+		return nil, t.errors.sorted()
+	}
+
+	t.prependPrologue()
+
 	return &object.Program{
 		WhitespaceInstructions: t.instructions,
+	}, nil
+}
+
+// HeapUsage is the peak number of heap addresses taken by global variables and constants, and by a single call frame.
+// Blocks allocated at runtime are not included.
+type HeapUsage struct {
+	GlobalHeapAddresses int64
+	LargestFrameSize    int64
+}
+
+// HeapUsage reports the heap addresses taken by the transpiled program
+func (t *Transpiler) HeapUsage() HeapUsage {
+	return HeapUsage{GlobalHeapAddresses: t.currentHeapAddress, LargestFrameSize: t.largestFrameSize}
+}
+
+// transpileStatements releases the heap addresses of variables after the last statement that mentions them
+func (t *Transpiler) transpileStatements(statements []ast.Statement, scopeContext *object.ScopeContext) {
+	lastUses := variableLastUses(statements)
+
+	for i, statement := range statements {
+		t.transpileStatement(statement, scopeContext)
+
+		for _, name := range lastUses[i] {
+			t.releaseVariable(name)
+		}
 	}
 }
 
+func (t *Transpiler) transpileStatement(statement ast.Statement, scopeContext *object.ScopeContext) {
+	defer recoverBailout(func() {})
+
+	t.transpile(statement, scopeContext)
+}
+
 func (t *Transpiler) transpile(node ast.Node, scopeContext *object.ScopeContext) object.Object {
 	switch node := node.(type) {
 	case *ast.LetStatement:
 		return t.transpileLetStatement(node)
 	case *ast.AssignmentStatement:
 		return t.transpileAssignmentStatement(node)
+	case *ast.IndexAssignmentStatement:
+		return t.transpileIndexAssignmentStatement(node)
+	case *ast.CompoundAssignmentStatement:
+		return t.transpileCompoundAssignmentStatement(node)
 	case *ast.IfStatement:
 		return t.transpileIfStatement(node, scopeContext)
 	case *ast.BlockStatement:
 		return t.transpileBlockStatement(node, scopeContext)
 	case *ast.ForStatement:
-		return t.transpileForStatement(node)
+		return t.transpileForStatement(node, scopeContext, nil)
+	case *ast.WhileStatement:
+		return t.transpileWhileStatement(node, scopeContext, nil)
+	case *ast.DoWhileStatement:
+		return t.transpileDoWhileStatement(node, scopeContext, nil)
+	case *ast.LabeledStatement:
+		return t.transpileLabeledStatement(node, scopeContext, nil)
 	case *ast.BreakStatement:
 		return t.transpileBreakStatement(node, scopeContext)
 	case *ast.ContinueStatement:
 		return t.transpileContinueStatement(node, scopeContext)
+	case *ast.FunctionDeclaration:
+		return t.transpileFunctionDeclaration(node)
+	case *ast.ReturnStatement:
+		return t.transpileReturnStatement(node)
 	case *ast.ExpressionStatement:
-		return t.transpile(node.Expression, scopeContext)
+		return t.transpileExpressionStatement(node)
 	case *ast.StringLiteral:
-		return t.transpileString([]byte(node.Value))
+		return &object.String{Constant: true, Value: node.Value}
 	case *ast.IntegerLiteral:
 		return t.transpileInteger(node.Value)
+	case *ast.BooleanLiteral:
+		return t.transpileBoolean(node.Value)
 	case *ast.Identifier:
 		return t.transpileIdentifier(node)
+	case *ast.ArrayLiteral:
+		return t.transpileArrayLiteral(node)
+	case *ast.IndexExpression:
+		return t.transpileIndexExpression(node)
+	case *ast.MemberExpression:
+		return t.transpileMemberExpression(node)
 	case *ast.CallExpression:
 		function := t.transpile(node.Function, scopeContext)
 		args := t.transpileExpressions(node.Arguments)
 
-		return t.applyFunction(function, args)
+		return t.applyFunction(node, function, args)
 	case *ast.PrefixExpression:
+		if node.Operator == ast.INCREMENT || node.Operator == ast.DECREMENT {
+			return t.transpileUpdateExpression(node, node.Right, node.Operator, true, true)
+		}
+
 		right := t.transpile(node.Right, scopeContext)
 
 		return t.transpilePrefixExpression(node, right)
 	case *ast.InfixExpression:
+		if node.Operator == ast.AND || node.Operator == ast.OR {
+			return t.transpileLogicalExpression(node)
+		}
+
 		left := t.transpile(node.Left, scopeContext)
 		right := t.transpile(node.Right, scopeContext)
 
 		return t.transpileInfixExpression(node, left, right)
 	case *ast.SuffixExpression:
-		left := t.transpile(node.Left, scopeContext)
-
-		return t.transpileSuffixExpression(node, left)
+		return t.transpileUpdateExpression(node, node.Left, node.Operator, false, true)
+	case *ast.ConditionalExpression:
+		return t.transpileConditionalExpression(node)
 	}
 
 	return &object.Void{}
 }
 
 func (t *Transpiler) transpileLetStatement(statement *ast.LetStatement) object.Object {
-	_, ok := t.environment.Get(statement.Name.Value)
+	_, ok := t.environment.GetLocal(statement.Name.Value)
 	if ok {
-		panic(fmt.Sprintf("[:%d] redeclaration of %s", statement.Token.LineNumber, statement.Name.Value))
+		t.addError(statement.Name, "redeclaration of %s", statement.Name.Value)
 	}
 
-	value := t.transpile(statement.Value, nil)
-	t.environment.Set(statement.Name.Value, value)
+	value := t.transpileValue(statement.Value)
+
+	// Values of variables are moved from the stack to their own heap address
+	variable := value
+
+	switch value := value.(type) {
+	case *object.Integer:
+		variable = &object.Integer{HeapAddress: t.getEmptyHeapAddress()}
+	case *object.Boolean:
+		variable = &object.Boolean{HeapAddress: t.getEmptyHeapAddress()}
+	case *object.String:
+		variable = &object.String{HeapAddress: t.getEmptyHeapAddress()}
+	case *object.Array:
+		variable = &object.Array{HeapAddress: t.getEmptyHeapAddress(), Element: value.Element}
+	}
+
+	if onStack(variable) {
+		t.storeTopStackValueInHeapInstruction(heapAddress(variable))
+	}
+
+	if statement.IsConstant() {
+		t.environment.SetConstant(statement.Name.Value, variable)
+	} else {
+		t.environment.Set(statement.Name.Value, variable)
+	}
 
 	return &object.Void{}
 }
 
 func (t *Transpiler) transpileAssignmentStatement(statement *ast.AssignmentStatement) object.Object {
-	previousValue, ok := t.environment.Get(statement.Name.Value)
+	variable := t.assignableVariable(statement.Name)
+	assignedValue := t.transpileValue(statement.Value)
+
+	t.storeVariable(statement, variable, assignedValue)
+
+	return &object.Void{}
+}
+
+func (t *Transpiler) assignableVariable(name *ast.Identifier) object.Object {
+	variable, ok := t.environment.Get(name.Value)
 	if !ok {
-		panic(fmt.Sprintf("[:%d] %s is not defined", statement.Token.LineNumber, statement.Name.Value))
+		t.addError(name, "%s is not defined", name.Value)
+	}
+
+	if t.environment.IsConstant(name.Value) {
+		t.addError(name, "assignment to constant variable %s", name.Value)
 	}
 
-	assignedValue := t.transpile(statement.Value, nil)
+	return variable
+}
+
+// Stack: value -> (empty)
+func (t *Transpiler) storeVariable(node ast.Node, variable, assignedValue object.Object) {
+	if !sameType(variable, assignedValue) {
+		t.addError(node, "assignment type mismatch %s = %s", describeType(variable), describeType(assignedValue))
+	}
+
+	switch variable := variable.(type) {
+	case *object.Integer, *object.Boolean, *object.String:
+		t.storeTopStackValueInHeapInstruction(heapAddress(variable))
+	case *object.Array:
+		if variable.Element == nil {
+			variable.Element = assignedValue.(*object.Array).Element
+		}
+
+		t.storeTopStackValueInHeapInstruction(variable.HeapAddress)
+	default:
+		t.addError(node, "%s cannot be reassigned", describeType(variable))
+	}
+}
+
+// transpileReference pushes the current value of a variable or an array element, preceded by the element address.
+// The returned function stores the value on top of the stack in the same place, keeping the given number of stack items
+// between the element address and the value.
+func (t *Transpiler) transpileReference(target ast.Expression) (object.Object, func(node ast.Node, value object.Object, keptItems int)) {
+	switch target := target.(type) {
+	case *ast.Identifier:
+		variable := t.assignableVariable(target)
+		t.pushValueInstruction(variable)
+
+		return variable, func(node ast.Node, value object.Object, keptItems int) {
+			t.storeVariable(node, variable, value)
+		}
+	case *ast.IndexExpression:
+		array := t.transpileIndexTarget(target)
+
+		if array.Element == nil {
+			array.Element = &object.Integer{}
+		}
+
+		// The element is looked up once, its address stays on the stack for the store
+		t.callRuntimeSubroutine(ARRAY_ELEMENT_ADDRESS_SUBROUTINE)
+		t.addInstruction(whitespace.DuplicateTopStackItem())
+		t.addInstruction(whitespace.RetrieveFromHeap())
+
+		return stackValue(array.Element), func(node ast.Node, value object.Object, keptItems int) {
+			t.setArrayElementType(node, array, value)
+
+			if keptItems == 0 {
+				t.addInstruction(whitespace.StoreInHeap())
+
+				return
+			}
+
+			t.addInstruction(whitespace.LiftStackItem(keptItems + 1))
+			t.addInstruction(whitespace.SwapTwoTopStackItems())
+			t.addInstruction(whitespace.StoreInHeap())
+			t.addInstruction(whitespace.SlideStackItems(keptItems))
+		}
+	default:
+		t.addError(target, "invalid assignment target")
+
+		return nil, nil
+	}
+}
+
+func (t *Transpiler) transpileCompoundAssignmentStatement(statement *ast.CompoundAssignmentStatement) object.Object {
+	currentValue, store := t.transpileReference(statement.Target)
+	value := t.transpile(statement.Value, nil)
+
+	result := t.transpileInfixExpression(&ast.InfixExpression{
+		Span:     statement.Span,
+		Token:    statement.Token,
+		Left:     statement.Target,
+		Operator: statement.Operator,
+		Right:    statement.Value,
+	}, currentValue, value)
+
+	store(statement, result, 0)
+
+	return &object.Void{}
+}
+
+func (t *Transpiler) transpileIndexAssignmentStatement(statement *ast.IndexAssignmentStatement) object.Object {
+	array := t.transpileIndexTarget(statement.Target)
+	t.callRuntimeSubroutine(ARRAY_ELEMENT_ADDRESS_SUBROUTINE)
+
+	value := t.transpileValue(statement.Value)
+	t.setArrayElementType(statement.Value, array, value)
+
+	t.addInstruction(whitespace.StoreInHeap())
+
+	return &object.Void{}
+}
 
-	if previousValue.Type() != assignedValue.Type() {
-		panic(
-			fmt.Sprintf("[:%d] assignment type mismatch %s = %s",
-				statement.Token.LineNumber,
-				previousValue.Type(),
-				assignedValue.Type(),
-			),
-		)
+// transpileExpressionStatement discards the value of the expression, updates skip computing it in the first place
+func (t *Transpiler) transpileExpressionStatement(statement *ast.ExpressionStatement) object.Object {
+	switch expression := statement.Expression.(type) {
+	case *ast.PrefixExpression:
+		if expression.Operator == ast.INCREMENT || expression.Operator == ast.DECREMENT {
+			return t.transpileUpdateExpression(expression, expression.Right, expression.Operator, true, false)
+		}
+	case *ast.SuffixExpression:
+		return t.transpileUpdateExpression(expression, expression.Left, expression.Operator, false, false)
 	}
 
-	if previousValue.Type() == object.INT_OBJ {
-		t.retrieveFromHeapInstruction(assignedValue.(*object.Integer).HeapAddress)
-		t.storeTopStackValueInHeapInstruction(previousValue.(*object.Integer).HeapAddress)
+	value := t.transpile(statement.Expression, nil)
+	if onStack(value) {
+		t.addInstruction(whitespace.DiscardTopStackItem())
 	}
 
 	return &object.Void{}
@@ -183,12 +588,7 @@ func (t *Transpiler) transpileIfStatement(statement *ast.IfStatement, scopeConte
 	alternativeLabel := t.getEmptyLabelId()
 	endIfLabel := t.getEmptyLabelId()
 
-	conditionResult := t.transpile(statement.Condition, nil)
-	if conditionResult.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("[:%d] invalid if condition expression", statement.Token.LineNumber))
-	}
-
-	t.retrieveFromHeapInstruction(conditionResult.(*object.Integer).HeapAddress)
+	t.transpileCondition(statement.Condition, "invalid if condition expression")
 	t.addInstruction(whitespace.JumpToLabelIfZero(alternativeLabel))
 
 	t.transpile(statement.Consequence, scopeContext)
@@ -206,487 +606,1045 @@ func (t *Transpiler) transpileIfStatement(statement *ast.IfStatement, scopeConte
 }
 
 func (t *Transpiler) transpileBlockStatement(block *ast.BlockStatement, scopeContext *object.ScopeContext) object.Object {
-	for _, statement := range block.Statements {
-		t.transpile(statement, scopeContext)
-	}
+	defer t.enterScope()()
+
+	t.transpileStatements(block.Statements, scopeContext)
 
 	return &object.Void{}
 }
 
-func (t *Transpiler) transpileForStatement(statement *ast.ForStatement) object.Object {
+// Stack: (empty) -> number that is zero when the condition is falsy
+func (t *Transpiler) transpileCondition(expression ast.Expression, errorMessage string) {
+	condition := t.transpile(expression, nil)
+
+	if !t.pushTruthinessInstruction(condition) {
+		t.addError(expression, "%s", errorMessage)
+	}
+}
+
+// pushTruthinessInstruction replaces the value on top of the stack with a number that is zero when the value is falsy
+func (t *Transpiler) pushTruthinessInstruction(value object.Object) bool {
+	switch value := value.(type) {
+	case *object.Integer, *object.Boolean:
+	case *object.String:
+		// Strings are truthy unless empty, the length leads their heap block
+		if value.Constant {
+			t.pushNumberLiteralToStackInstruction(int64(len([]rune(value.Value))))
+
+			return true
+		}
+
+		t.addInstruction(whitespace.RetrieveFromHeap())
+	case *object.Array:
+		t.addInstruction(whitespace.DiscardTopStackItem())
+		t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// enterScope encloses the current environment, the returned function restores it
+func (t *Transpiler) enterScope() func() {
+	previousEnvironment := t.environment
+	t.environment = object.NewEnclosedEnvironment(previousEnvironment)
+
+	return func() { t.environment = previousEnvironment }
+}
+
+func (t *Transpiler) transpileForStatement(statement *ast.ForStatement, scopeContext *object.ScopeContext, labels []string) object.Object {
 	loopControlLabel := t.getEmptyLabelId()
 	loopBodyLabel := t.getEmptyLabelId()
 	loopEndLabel := t.getEmptyLabelId()
 
-	t.transpile(statement.Declaration, nil)
+	// The loop declaration is scoped to the loop, enclosing the body block
+	defer t.enterScope()()
 
-	initialConditionResult := t.transpile(statement.Boundary, nil)
-	if initialConditionResult.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("[:%d] invalid loop boundary condition expression", statement.Token.LineNumber))
-	}
+	t.transpile(statement.Declaration, nil)
 
-	t.retrieveFromHeapInstruction(initialConditionResult.(*object.Integer).HeapAddress)
+	t.transpileCondition(statement.Boundary, "invalid loop boundary condition expression")
 	t.addInstruction(whitespace.JumpToLabelIfZero(loopEndLabel))
 
 	t.addInstruction(whitespace.JumpToLabel(loopBodyLabel))
 
 	t.addInstruction(whitespace.Label(loopControlLabel))
 
-	incrementResult := t.transpile(statement.Increment, nil)
-	if incrementResult.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("[:%d] invalid loop increment expression", statement.Token.LineNumber))
-	}
-
-	previousIteratorValue, _ := t.environment.Get(statement.Declaration.Name.Value)
-	previousIteratorValueHeapAddress := previousIteratorValue.(*object.Integer).HeapAddress
+	t.transpile(statement.Increment, nil)
 
-	t.retrieveFromHeapInstruction(incrementResult.(*object.Integer).HeapAddress)
-	t.storeTopStackValueInHeapInstruction(previousIteratorValueHeapAddress)
-
-	conditionResult := t.transpile(statement.Boundary, nil)
-	if conditionResult.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("[:%d] invalid loop boundary condition expression", statement.Token.LineNumber))
-	}
-
-	t.retrieveFromHeapInstruction(conditionResult.(*object.Integer).HeapAddress)
+	t.transpileCondition(statement.Boundary, "invalid loop boundary condition expression")
 	t.addInstruction(whitespace.JumpToLabelIfZero(loopEndLabel))
 
 	t.addInstruction(whitespace.Label(loopBodyLabel))
 
 	t.transpile(statement.Body, &object.ScopeContext{
-		For: &object.ForContext{
-			ControlLabelId: loopControlLabel,
-			EndLabelId:     loopEndLabel,
-		},
+		Outer:      scopeContext,
+		Labels:     labels,
+		Loop:       &object.LoopContext{ControlLabelId: loopControlLabel},
+		EndLabelId: loopEndLabel,
 	})
 
 	t.addInstruction(whitespace.JumpToLabel(loopControlLabel))
 
 	t.addInstruction(whitespace.Label(loopEndLabel))
 
+	t.releaseVariable(statement.Declaration.Name.Value)
+
 	return &object.Void{}
 }
 
-func (t *Transpiler) transpileBreakStatement(statement *ast.BreakStatement, scopeContext *object.ScopeContext) object.Object {
-	if scopeContext == nil {
-		panic(fmt.Sprintf("[:%d] cannot determine break target", statement.Token.LineNumber))
-	}
+func (t *Transpiler) transpileWhileStatement(statement *ast.WhileStatement, scopeContext *object.ScopeContext, labels []string) object.Object {
+	loopControlLabel := t.getEmptyLabelId()
+	loopEndLabel := t.getEmptyLabelId()
 
-	t.addInstruction(whitespace.JumpToLabel(scopeContext.For.EndLabelId))
+	t.addInstruction(whitespace.Label(loopControlLabel))
 
-	return &object.Void{}
-}
+	t.transpileCondition(statement.Condition, "invalid loop condition expression")
+	t.addInstruction(whitespace.JumpToLabelIfZero(loopEndLabel))
 
-func (t *Transpiler) transpileContinueStatement(statement *ast.ContinueStatement, scopeContext *object.ScopeContext) object.Object {
-	if scopeContext == nil {
-		panic(fmt.Sprintf("[:%d] cannot determine continue target", statement.Token.LineNumber))
-	}
+	t.transpile(statement.Body, &object.ScopeContext{
+		Outer:      scopeContext,
+		Labels:     labels,
+		Loop:       &object.LoopContext{ControlLabelId: loopControlLabel},
+		EndLabelId: loopEndLabel,
+	})
 
-	t.addInstruction(whitespace.JumpToLabel(scopeContext.For.ControlLabelId))
+	t.addInstruction(whitespace.JumpToLabel(loopControlLabel))
+
+	t.addInstruction(whitespace.Label(loopEndLabel))
 
 	return &object.Void{}
 }
 
-func (t *Transpiler) transpileIdentifier(identifier *ast.Identifier) object.Object {
-	if val, ok := t.environment.Get(identifier.Value); ok {
-		return val
-	}
+func (t *Transpiler) transpileDoWhileStatement(statement *ast.DoWhileStatement, scopeContext *object.ScopeContext, labels []string) object.Object {
+	loopBodyLabel := t.getEmptyLabelId()
+	loopControlLabel := t.getEmptyLabelId()
+	loopEndLabel := t.getEmptyLabelId()
 
-	if buildInFunction, ok := t.builtInFunctions[identifier.Value]; ok {
-		return buildInFunction
-	}
+	t.addInstruction(whitespace.Label(loopBodyLabel))
+
+	t.transpile(statement.Body, &object.ScopeContext{
+		Outer:      scopeContext,
+		Labels:     labels,
+		Loop:       &object.LoopContext{ControlLabelId: loopControlLabel},
+		EndLabelId: loopEndLabel,
+	})
+
+	t.addInstruction(whitespace.Label(loopControlLabel))
+
+	t.transpileCondition(statement.Condition, "invalid loop condition expression")
+	t.addInstruction(whitespace.JumpToLabelIfZero(loopEndLabel))
+
+	t.addInstruction(whitespace.JumpToLabel(loopBodyLabel))
+
+	t.addInstruction(whitespace.Label(loopEndLabel))
 
-	panic(fmt.Sprintf("[:%d] %s is not defined", identifier.Token.LineNumber, identifier.Value))
+	return &object.Void{}
 }
 
-func (t *Transpiler) transpileString(value []byte) object.Object {
-	var stringObject object.String
+func (t *Transpiler) transpileLabeledStatement(statement *ast.LabeledStatement, scopeContext *object.ScopeContext, labels []string) object.Object {
+	if scopeContext.FindLabel(statement.Label.Value) != nil || slices.Contains(labels, statement.Label.Value) {
+		t.addError(statement.Label, "label %s has already been declared", statement.Label.Value)
+	}
 
-	for _, c := range value {
-		heapAddress := t.getEmptyHeapAddress()
-		t.storeValueInHeapInstruction(heapAddress, int64(c))
+	labels = append(labels, statement.Label.Value)
 
-		stringObject.Chars = append(stringObject.Chars, object.Char{HeapAddress: t.currentHeapAddress})
+	switch body := statement.Body.(type) {
+	case *ast.LabeledStatement:
+		return t.transpileLabeledStatement(body, scopeContext, labels)
+	case *ast.ForStatement:
+		return t.transpileForStatement(body, scopeContext, labels)
+	case *ast.WhileStatement:
+		return t.transpileWhileStatement(body, scopeContext, labels)
+	case *ast.DoWhileStatement:
+		return t.transpileDoWhileStatement(body, scopeContext, labels)
 	}
 
-	return &stringObject
-}
+	endLabel := t.getEmptyLabelId()
 
-func (t *Transpiler) transpileInteger(value int64) object.Object {
-	var integerObject object.Integer
+	t.transpile(statement.Body, &object.ScopeContext{
+		Outer:      scopeContext,
+		Labels:     labels,
+		EndLabelId: endLabel,
+	})
 
-	integerObject.HeapAddress = t.getEmptyHeapAddress()
-	t.storeValueInHeapInstruction(integerObject.HeapAddress, value)
+	t.addInstruction(whitespace.Label(endLabel))
 
-	return &integerObject
+	return &object.Void{}
 }
 
-func (t *Transpiler) transpileExpressions(expressions []ast.Expression) []object.Object {
-	var result []object.Object
+func (t *Transpiler) transpileBreakStatement(statement *ast.BreakStatement, scopeContext *object.ScopeContext) object.Object {
+	if statement.Label != nil {
+		target := scopeContext.FindLabel(statement.Label.Value)
+		if target == nil {
+			t.addError(statement.Label, "undefined label %s", statement.Label.Value)
+		}
 
-	for _, expression := range expressions {
-		transpiledExpression := t.transpile(expression, nil)
+		t.addInstruction(whitespace.JumpToLabel(target.EndLabelId))
 
-		result = append(result, transpiledExpression)
+		return &object.Void{}
 	}
 
-	return result
+	target := scopeContext.FindLoop()
+	if target == nil {
+		t.addError(statement, "cannot determine break target")
+	}
+
+	t.addInstruction(whitespace.JumpToLabel(target.EndLabelId))
+
+	return &object.Void{}
 }
 
-func (t *Transpiler) applyFunction(function object.Object, args []object.Object) object.Object {
-	switch function := function.(type) {
-	case *object.BuiltIn:
-		return function.Function(args...)
+func (t *Transpiler) transpileContinueStatement(statement *ast.ContinueStatement, scopeContext *object.ScopeContext) object.Object {
+	if statement.Label != nil {
+		target := scopeContext.FindLabel(statement.Label.Value)
+		if target == nil {
+			t.addError(statement.Label, "undefined label %s", statement.Label.Value)
+		}
+
+		if target.Loop == nil {
+			t.addError(statement.Label, "label %s does not denote a loop", statement.Label.Value)
+		}
+
+		t.addInstruction(whitespace.JumpToLabel(target.Loop.ControlLabelId))
+
+		return &object.Void{}
+	}
+
+	target := scopeContext.FindLoop()
+	if target == nil {
+		t.addError(statement, "cannot determine continue target")
+	}
+
+	t.addInstruction(whitespace.JumpToLabel(target.Loop.ControlLabelId))
+
+	return &object.Void{}
+}
+
+func (t *Transpiler) transpileIdentifier(identifier *ast.Identifier) object.Object {
+	if val, ok := t.environment.Get(identifier.Value); ok {
+		t.pushValueInstruction(val)
+
+		return val
+	}
+
+	if buildInFunction, ok := t.builtInFunctions[identifier.Value]; ok {
+		return buildInFunction
+	}
+
+	t.addError(identifier, "%s is not defined", identifier.Value)
+
+	return nil
+}
+
+func (t *Transpiler) transpileInteger(value int64) object.Object {
+	t.pushNumberLiteralToStackInstruction(value)
+
+	return &object.Integer{}
+}
+
+func (t *Transpiler) transpileBoolean(value bool) object.Object {
+	if value {
+		t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+	} else {
+		t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
+	}
+
+	return &object.Boolean{}
+}
+
+// transpileArrayLiteral allocates the array first, each element is stored as soon as it is evaluated
+func (t *Transpiler) transpileArrayLiteral(literal *ast.ArrayLiteral) object.Object {
+	array := &object.Array{}
+
+	capacity := max(int64(len(literal.Elements)), minimumArrayCapacity)
+
+	// The header and the first block of elements are allocated together
+	t.pushNumberLiteralToStackInstruction(arrayHeaderSize + capacity)
+	t.allocateInstruction()
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.pushNumberLiteralToStackInstruction(int64(len(literal.Elements)))
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.pushNumberLiteralToStackInstruction(arrayCapacityHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.pushNumberLiteralToStackInstruction(capacity)
+	t.addInstruction(whitespace.StoreInHeap())
+
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.pushNumberLiteralToStackInstruction(arrayElementsHeaderOffset)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.LiftStackItem(1))
+	t.pushNumberLiteralToStackInstruction(arrayHeaderSize)
+	t.addInstruction(whitespace.Add())
+	t.addInstruction(whitespace.StoreInHeap())
+
+	for i, element := range literal.Elements {
+		t.addInstruction(whitespace.DuplicateTopStackItem())
+		t.pushNumberLiteralToStackInstruction(arrayHeaderSize + int64(i))
+		t.addInstruction(whitespace.Add())
+
+		value := t.transpileValue(element)
+		t.setArrayElementType(element, array, value)
+
+		t.addInstruction(whitespace.StoreInHeap())
+	}
+
+	return array
+}
+
+func (t *Transpiler) setArrayElementType(node ast.Node, array *object.Array, element object.Object) {
+	switch element := element.(type) {
+	case *object.Integer:
+		if array.Element == nil {
+			array.Element = &object.Integer{}
+		}
+	case *object.Boolean:
+		if array.Element == nil {
+			array.Element = &object.Boolean{}
+		}
+	case *object.String:
+		if array.Element == nil {
+			array.Element = &object.String{}
+		}
+	case *object.Array:
+		if array.Element == nil {
+			array.Element = &object.Array{Element: element.Element}
+		}
 	default:
-		panic(fmt.Sprintf("%s is not a function", function.Type()))
+		t.addError(node, "unsupported array element %s", element.Type())
+	}
+
+	if !sameType(array.Element, element) {
+		t.addError(node, "array element type mismatch %s, %s", describeType(array.Element), describeType(element))
 	}
 }
 
-func (t *Transpiler) transpilePrefixExpression(expression *ast.PrefixExpression, right object.Object) object.Object {
-	switch expression.Operator {
-	case ast.SUBTRACTION:
-		return t.transpileMinusPrefixOperatorExpression(right)
-	case ast.NEGATION:
-		return t.transpileNegationPrefixOperatorExpression(right)
+// Stack: (empty) -> array, index
+func (t *Transpiler) transpileIndexTarget(expression *ast.IndexExpression) *object.Array {
+	left := t.transpile(expression.Left, nil)
+
+	array, ok := left.(*object.Array)
+	if !ok {
+		t.addError(expression.Left, "cannot index %s", left.Type())
+	}
+
+	index := t.transpile(expression.Index, nil)
+	if index.Type() != object.INT_OBJ {
+		t.addError(expression.Index, "invalid array index %s", index.Type())
+	}
+
+	return array
+}
+
+func (t *Transpiler) transpileIndexExpression(expression *ast.IndexExpression) object.Object {
+	array := t.transpileIndexTarget(expression)
+
+	// Elements read before the source adds any are assumed to be numbers, adding anything else later is a type mismatch
+	if array.Element == nil {
+		array.Element = &object.Integer{}
+	}
+
+	t.callRuntimeSubroutine(ARRAY_ELEMENT_ADDRESS_SUBROUTINE)
+	t.addInstruction(whitespace.RetrieveFromHeap())
+
+	return stackValue(array.Element)
+}
+
+// stackValue is a value of the type of the template, left on the stack
+func stackValue(template object.Object) object.Object {
+	switch template := template.(type) {
+	case *object.Boolean:
+		return &object.Boolean{}
+	case *object.String:
+		return &object.String{}
+	case *object.Array:
+		return &object.Array{Element: template.Element}
+	default:
+		return &object.Integer{}
+	}
+}
+
+func (t *Transpiler) transpileMemberExpression(expression *ast.MemberExpression) object.Object {
+	if namespace, ok := expression.Object.(*ast.Identifier); ok {
+		if _, isVariable := t.environment.Get(namespace.Value); !isVariable {
+			if buildInFunction, ok := t.builtInFunctions[namespace.Value+"."+expression.Property.Value]; ok {
+				return buildInFunction
+			}
+		}
+	}
+
+	value := t.transpile(expression.Object, nil)
+
+	array, ok := value.(*object.Array)
+	if !ok {
+		t.addError(expression.Property, "property %s does not exist on %s", expression.Property.Value, value.Type())
+	}
+
+	switch expression.Property.Value {
+	case "length":
+		t.addInstruction(whitespace.RetrieveFromHeap())
+
+		return &object.Integer{}
+	case "push":
+		return &object.BuiltIn{Function: func(args ...object.Object) (object.Object, error) {
+			return t.arrayPush(expression, array, args)
+		}}
 	default:
-		panic(fmt.Sprintf("[:%d] unknown operator %s%s", expression.Token.LineNumber, expression.Operator, right.Type()))
+		t.addError(expression.Property, "property %s does not exist on %s", expression.Property.Value, value.Type())
+
+		return nil
+	}
+}
+
+// Stack: array, element -> length
+func (t *Transpiler) arrayPush(expression *ast.MemberExpression, array *object.Array, args []object.Object) (object.Object, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("push expects 1 argument(s), got %d", len(args))
+	}
+
+	t.setArrayElementType(expression, array, args[0])
+
+	if element, ok := args[0].(*object.String); ok {
+		t.pushStringAddressInstruction(element)
+	}
+
+	t.callRuntimeSubroutine(ARRAY_PUSH_SUBROUTINE)
+
+	return &object.Integer{}, nil
+}
+
+// pushValueInstruction pushes the value of a variable
+func (t *Transpiler) pushValueInstruction(variable object.Object) {
+	if onStack(variable) {
+		t.retrieveFromHeapInstruction(heapAddress(variable))
+	}
+}
+
+// onStack reports whether a value takes an item on the stack, constant strings are only pushed when needed
+func onStack(value object.Object) bool {
+	switch value := value.(type) {
+	case *object.Integer, *object.Boolean, *object.Array:
+		return true
+	case *object.String:
+		return !value.Constant
+	default:
+		return false
+	}
+}
+
+func heapAddress(variable object.Object) int64 {
+	switch variable := variable.(type) {
+	case *object.Integer:
+		return variable.HeapAddress
+	case *object.Boolean:
+		return variable.HeapAddress
+	case *object.String:
+		return variable.HeapAddress
+	case *object.Array:
+		return variable.HeapAddress
+	default:
+		return 0
+	}
+}
+
+// transpileValue transpiles an expression whose value has to be on the stack, pushing constant strings
+func (t *Transpiler) transpileValue(expression ast.Expression) object.Object {
+	value := t.transpile(expression, nil)
+
+	if str, ok := value.(*object.String); ok && str.Constant {
+		t.pushStringAddressInstruction(str)
+
+		return &object.String{}
+	}
+
+	return value
+}
+
+func sameType(left, right object.Object) bool {
+	if left.Type() != right.Type() {
+		return false
+	}
+
+	leftArray, ok := left.(*object.Array)
+	if !ok {
+		return true
+	}
+
+	rightArray := right.(*object.Array)
+	if leftArray.Element == nil || rightArray.Element == nil {
+		return true
+	}
+
+	return sameType(leftArray.Element, rightArray.Element)
+}
+
+func describeType(value object.Object) string {
+	if array, ok := value.(*object.Array); ok && array.Element != nil {
+		return fmt.Sprintf("%s<%s>", array.Type(), describeType(array.Element))
+	}
+
+	return string(value.Type())
+}
+
+func (t *Transpiler) transpileExpressions(expressions []ast.Expression) []object.Object {
+	var result []object.Object
+
+	for _, expression := range expressions {
+		result = append(result, t.transpile(expression, nil))
+	}
+
+	return result
+}
+
+func (t *Transpiler) applyFunction(call *ast.CallExpression, function object.Object, args []object.Object) object.Object {
+	switch function := function.(type) {
+	case *object.BuiltIn:
+		result, err := function.Function(args...)
+		if err != nil {
+			t.addError(call, "%v", err)
+		}
+
+		return result
+	case *object.Function:
+		return t.callFunction(call, function, args)
+	default:
+		t.addError(call, "%s is not a function", function.Type())
+
+		return nil
+	}
+}
+
+func (t *Transpiler) declareFunctions(statements []ast.Statement) {
+	for _, statement := range statements {
+		if declaration, ok := statement.(*ast.FunctionDeclaration); ok {
+			t.declareFunction(declaration)
+		}
 	}
 }
 
-func (t *Transpiler) transpileMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("unsupported opposition target %q", right.Type()))
+func (t *Transpiler) declareFunction(declaration *ast.FunctionDeclaration) {
+	defer recoverBailout(func() {})
+
+	if _, ok := t.environment.GetLocal(declaration.Name.Value); ok {
+		t.addError(declaration.Name, "redeclaration of %s", declaration.Name.Value)
 	}
 
-	rightInteger := right.(*object.Integer)
+	function := &object.Function{
+		Declaration:    declaration,
+		LabelId:        t.getEmptyLabelId(),
+		ReturnsValue:   containsReturnValue(declaration.Body.Statements),
+		ParameterTypes: make([]object.ObjectType, len(declaration.Parameters)),
+	}
 
-	t.literalMultiplicationInstruction(rightInteger.HeapAddress, -1)
+	if known, ok := t.signatures[declaration.Name.Value]; ok {
+		copy(function.ParameterTypes, known.ParameterTypes)
+		function.ReturnType = known.ReturnType
+	}
 
-	resultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(resultHeapAddress)
+	t.functions = append(t.functions, function)
+	t.environment.Set(declaration.Name.Value, function)
+}
 
-	rightInteger.HeapAddress = resultHeapAddress
+func (t *Transpiler) transpileFunctionDeclaration(declaration *ast.FunctionDeclaration) object.Object {
+	t.addError(declaration, "function declarations are only supported at the top level")
 
-	return rightInteger
+	return nil
 }
 
-func (t *Transpiler) transpileNegationPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INT_OBJ {
-		panic(fmt.Sprintf("unsupported opposition target %q", right.Type()))
+func containsReturnValue(statements []ast.Statement) bool {
+	for _, statement := range statements {
+		switch statement := statement.(type) {
+		case *ast.ReturnStatement:
+			if statement.Value != nil {
+				return true
+			}
+		case *ast.BlockStatement:
+			if containsReturnValue(statement.Statements) {
+				return true
+			}
+		case *ast.IfStatement:
+			if containsReturnValue(statement.Consequence.Statements) {
+				return true
+			}
+
+			if statement.Alternative != nil && containsReturnValue(statement.Alternative.Statements) {
+				return true
+			}
+		case *ast.ForStatement:
+			if containsReturnValue(statement.Body.Statements) {
+				return true
+			}
+		case *ast.WhileStatement:
+			if containsReturnValue(statement.Body.Statements) {
+				return true
+			}
+		case *ast.DoWhileStatement:
+			if containsReturnValue(statement.Body.Statements) {
+				return true
+			}
+		case *ast.LabeledStatement:
+			if containsReturnValue([]ast.Statement{statement.Body}) {
+				return true
+			}
+		}
 	}
 
-	rightInteger := right.(*object.Integer)
+	return false
+}
+
+func (t *Transpiler) transpileFunction(function *object.Function) {
+	defer recoverBailout(func() {})
+
+	declaration := function.Declaration
+
+	previousEnvironment := t.environment
+	t.environment = object.NewEnclosedEnvironment(previousEnvironment)
+	t.frame = &frame{function: function}
+
+	defer func() {
+		t.environment = previousEnvironment
+		t.frame = nil
+	}()
+
+	t.addInstruction(whitespace.Label(function.LabelId))
+
+	// The caller passes the arguments followed by the base address of the new frame on the stack
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.retrieveFromHeapInstruction(framePointerHeapAddress)
+	t.addInstruction(whitespace.StoreInHeap())
+	t.storeTopStackValueInHeapInstruction(framePointerHeapAddress)
+
+	parameterHeapAddresses := make([]int64, len(declaration.Parameters))
 
-	comparatorHeapAddress := t.getEmptyHeapAddress()
-	t.storeValueInHeapInstruction(comparatorHeapAddress, 0)
+	for i, parameter := range declaration.Parameters {
+		if _, ok := t.environment.GetLocal(parameter.Value); ok {
+			t.addError(parameter, "duplicate parameter %s", parameter.Value)
+		}
+
+		parameterHeapAddresses[i] = t.getEmptyHeapAddress()
+
+		// Parameters of functions never called are numbers
+		if function.ParameterTypes[i] == object.BOOLEAN_OBJ {
+			t.environment.Set(parameter.Value, &object.Boolean{HeapAddress: parameterHeapAddresses[i]})
+		} else {
+			t.environment.Set(parameter.Value, &object.Integer{HeapAddress: parameterHeapAddresses[i]})
+		}
+	}
+
+	for i := len(parameterHeapAddresses) - 1; i >= 0; i-- {
+		t.storeTopStackValueInHeapInstruction(parameterHeapAddresses[i])
+	}
 
-	t.comparisonInstruction(ast.EQUALS, rightInteger.HeapAddress, comparatorHeapAddress)
+	t.transpileStatements(declaration.Body.Statements, nil)
 
-	resultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(resultHeapAddress)
+	t.returnInstruction()
 
-	rightInteger.HeapAddress = resultHeapAddress
+	// The frame also holds the frame pointer of the caller
+	t.largestFrameSize = max(t.largestFrameSize, t.frame.size+1)
 
-	return rightInteger
+	for _, instructionIndex := range t.frame.sizeInstructionIndexes {
+		t.instructions[instructionIndex] = whitespace.PushToStack(t.frame.size + 1)
+	}
 }
 
+func (t *Transpiler) transpileReturnStatement(statement *ast.ReturnStatement) object.Object {
+	if t.frame == nil {
+		t.addError(statement, "return outside of function")
+	}
+
+	if statement.Value == nil {
+		t.returnInstruction()
+
+		return &object.Void{}
+	}
+
+	value := t.transpile(statement.Value, nil)
+	if value.Type() != object.INT_OBJ && value.Type() != object.BOOLEAN_OBJ {
+		t.addError(statement.Value, "unsupported return value %s", value.Type())
+	}
+
+	function := t.frame.function
+
+	switch {
+	case function.ReturnType == "":
+		if !t.guessedResults[value] {
+			function.ReturnType = value.Type()
+		}
+	case function.ReturnType != value.Type():
+		t.addError(statement.Value, "return type mismatch %s, %s", function.ReturnType, value.Type())
+	}
+
+	t.restoreFramePointerInstruction()
+	t.addInstruction(whitespace.EndSubroutine())
+
+	return &object.Void{}
+}
+
+func (t *Transpiler) returnInstruction() {
+	if t.frame.function.ReturnsValue {
+		t.pushNumberLiteralToStackInstruction(0)
+	}
+
+	t.restoreFramePointerInstruction()
+	t.addInstruction(whitespace.EndSubroutine())
+}
+
+func (t *Transpiler) restoreFramePointerInstruction() {
+	t.pushNumberLiteralToStackInstruction(framePointerHeapAddress)
+	t.retrieveFromHeapInstruction(framePointerHeapAddress)
+	t.addInstruction(whitespace.RetrieveFromHeap())
+	t.addInstruction(whitespace.StoreInHeap())
+}
+
+// Stack: arguments -> result
+func (t *Transpiler) callFunction(call *ast.CallExpression, function *object.Function, args []object.Object) object.Object {
+	declaration := function.Declaration
+
+	if len(args) != len(declaration.Parameters) {
+		t.addError(call, "%s expects %d argument(s), got %d", declaration.Name.Value, len(declaration.Parameters), len(args))
+	}
+
+	for i, arg := range args {
+		if arg.Type() != object.INT_OBJ && arg.Type() != object.BOOLEAN_OBJ {
+			t.addError(call.Arguments[i], "argument %s not supported", arg.Type())
+		}
+
+		switch {
+		case function.ParameterTypes[i] == "":
+			if !t.guessedResults[arg] {
+				function.ParameterTypes[i] = arg.Type()
+			}
+		case function.ParameterTypes[i] != arg.Type():
+			t.addError(call.Arguments[i], "argument type mismatch %s, %s", function.ParameterTypes[i], arg.Type())
+		}
+	}
+
+	// The frame of the callee starts right after the frame of the caller
+	t.retrieveFromHeapInstruction(framePointerHeapAddress)
+
+	if t.frame != nil {
+		t.frame.sizeInstructionIndexes = append(t.frame.sizeInstructionIndexes, len(t.instructions))
+		t.pushNumberLiteralToStackInstruction(0)
+		t.addInstruction(whitespace.Add())
+	}
+
+	t.addInstruction(whitespace.CallSubroutine(function.LabelId))
+
+	if !function.ReturnsValue {
+		return &object.Void{}
+	}
+
+	switch function.ReturnType {
+	case object.BOOLEAN_OBJ:
+		return &object.Boolean{}
+	case object.INT_OBJ:
+		return &object.Integer{}
+	}
+
+	result := &object.Integer{}
+	t.guessedResults[result] = true
+
+	return result
+}
+
+func (t *Transpiler) transpilePrefixExpression(expression *ast.PrefixExpression, right object.Object) object.Object {
+	switch expression.Operator {
+	case ast.SUBTRACTION:
+		if right.Type() != object.INT_OBJ {
+			t.addError(expression, "unsupported %s target %q", expression.Operator, right.Type())
+		}
+
+		t.literalStackMultiplicationInstruction(-1)
+
+		return &object.Integer{}
+	case ast.NEGATION:
+		if !t.pushTruthinessInstruction(right) {
+			t.addError(expression, "unsupported %s target %q", expression.Operator, right.Type())
+		}
+
+		return t.transpileNegationPrefixOperatorExpression()
+	default:
+		t.addError(expression, "unknown operator %s%s", expression.Operator, right.Type())
+
+		return nil
+	}
+}
+
+func (t *Transpiler) transpileNegationPrefixOperatorExpression() object.Object {
+	falsyLabel := t.getEmptyLabelId()
+	endNegationLabel := t.getEmptyLabelId()
+
+	t.addInstruction(whitespace.JumpToLabelIfZero(falsyLabel))
+
+	t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
+	t.addInstruction(whitespace.JumpToLabel(endNegationLabel))
+
+	t.addInstruction(whitespace.Label(falsyLabel))
+	t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+
+	t.addInstruction(whitespace.Label(endNegationLabel))
+
+	return &object.Boolean{}
+}
+
+// Stack: left, right -> result
 func (t *Transpiler) transpileInfixExpression(expression *ast.InfixExpression, left, right object.Object) object.Object {
 	switch {
 	case left.Type() == object.INT_OBJ && right.Type() == object.INT_OBJ:
 		return t.transpileIntegerInfixExpression(expression, left, right)
+	case left.Type() == object.BOOLEAN_OBJ && right.Type() == object.BOOLEAN_OBJ:
+		return t.transpileBooleanInfixExpression(expression, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return t.transpileStringInfixExpression(expression, left, right)
 	case left.Type() != right.Type():
-		panic(
-			fmt.Sprintf("[:%d] type mismatch %s %s %s",
-				expression.Token.LineNumber,
-				left.Type(),
-				expression.Operator,
-				right.Type(),
-			),
-		)
+		t.addError(expression, "type mismatch %s %s %s", left.Type(), expression.Operator, right.Type())
 	default:
-		panic(
-			fmt.Sprintf("[:%d] unknown operator %s %s %s",
-				expression.Token.LineNumber,
-				left.Type(),
-				expression.Operator,
-				right.Type(),
-			),
-		)
+		t.addError(expression, "unknown operator %s %s %s", left.Type(), expression.Operator, right.Type())
 	}
+
+	return nil
 }
 
 func (t *Transpiler) transpileIntegerInfixExpression(expression *ast.InfixExpression, left, right object.Object) object.Object {
-	leftHeapAddress := left.(*object.Integer).HeapAddress
-	rightHeapAddress := right.(*object.Integer).HeapAddress
-
 	switch expression.Operator {
 	case ast.ADDITION:
-		t.additionInstruction(leftHeapAddress, rightHeapAddress)
+		t.addInstruction(whitespace.Add())
 	case ast.SUBTRACTION:
-		t.subtractionInstruction(leftHeapAddress, rightHeapAddress)
+		t.addInstruction(whitespace.Subtract())
 	case ast.MULTIPLICATION:
-		t.multiplicationInstruction(leftHeapAddress, rightHeapAddress)
+		t.addInstruction(whitespace.Multiply())
 	case ast.DIVISION:
-		t.divisionInstruction(leftHeapAddress, rightHeapAddress)
+		t.addInstruction(whitespace.Divide())
 	case ast.MODULO:
-		t.moduloInstruction(leftHeapAddress, rightHeapAddress)
+		t.addInstruction(whitespace.Mod())
 	case ast.EQUALS, ast.NOT_EQUALS, ast.LESS_THAN, ast.LESS_THAN_OR_EQUAL, ast.GREATER_THAN, ast.GREATER_THAN_OR_EQUAL:
-		t.comparisonInstruction(expression.Operator, leftHeapAddress, rightHeapAddress)
-	case ast.AND:
-		t.andInstruction(leftHeapAddress, rightHeapAddress)
-	case ast.OR:
-		t.orInstruction(leftHeapAddress, rightHeapAddress)
+		t.comparisonInstruction(expression.Operator)
+
+		return &object.Boolean{}
 	default:
-		panic(
-			fmt.Sprintf("[:%d] unknown operator %s %s %s",
-				expression.Token.LineNumber,
-				left.Type(),
-				expression.Operator,
-				right.Type(),
-			),
-		)
+		t.addError(expression, "unknown operator %s %s %s", left.Type(), expression.Operator, right.Type())
 	}
 
-	resultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(resultHeapAddress)
+	return &object.Integer{}
+}
+
+func (t *Transpiler) transpileBooleanInfixExpression(expression *ast.InfixExpression, left, right object.Object) object.Object {
+	switch expression.Operator {
+	case ast.EQUALS, ast.NOT_EQUALS:
+		t.comparisonInstruction(expression.Operator)
+
+		return &object.Boolean{}
+	default:
+		t.addError(expression, "unknown operator %s %s %s", left.Type(), expression.Operator, right.Type())
 
-	return &object.Integer{HeapAddress: resultHeapAddress}
+		return nil
+	}
 }
 
 func (t *Transpiler) transpileStringInfixExpression(expression *ast.InfixExpression, left, right object.Object) object.Object {
-	leftChars := left.(*object.String).Chars
-	rightChars := right.(*object.String).Chars
-
 	switch expression.Operator {
 	case ast.ADDITION:
-		return &object.String{Chars: append(leftChars, rightChars...)} // This is naive and won't work for runtime assignment statements without dynamic memory allocation
+		return t.concatStringsInstruction(left.(*object.String), right.(*object.String))
 	default:
-		panic(
-			fmt.Sprintf("[:%d] unknown operator %s %s %s",
-				expression.Token.LineNumber,
-				left.Type(),
-				expression.Operator,
-				right.Type(),
-			),
-		)
+		t.addError(expression, "unknown operator %s %s %s", left.Type(), expression.Operator, right.Type())
+
+		return nil
 	}
 }
 
-func (t *Transpiler) transpileSuffixExpression(expression *ast.SuffixExpression, operand object.Object) object.Object {
-	if operand.Type() != object.INT_OBJ {
-		panic(
-			fmt.Sprintf("[:%d] unsupported %s target %q",
-				expression.Token.LineNumber,
-				expression.Operator,
-				operand.Type()),
-		)
-	}
+// transpileConditionalExpression evaluates one of the branches, both leave their value on the stack
+func (t *Transpiler) transpileConditionalExpression(expression *ast.ConditionalExpression) object.Object {
+	alternativeLabel := t.getEmptyLabelId()
+	endConditionalLabel := t.getEmptyLabelId()
 
-	switch expression.Operator {
-	case ast.INCREMENT:
-		t.literalAdditionInstruction(operand.(*object.Integer).HeapAddress, 1)
-	case ast.DECREMENT:
-		t.literalSubtractionInstruction(operand.(*object.Integer).HeapAddress, 1)
-	default:
-		panic(
-			fmt.Sprintf("[:%d] unknown operator %s %s",
-				expression.Token.LineNumber,
-				operand.Type(),
-				expression.Operator,
-			),
-		)
-	}
+	t.transpileCondition(expression.Condition, "invalid conditional expression condition")
+	t.addInstruction(whitespace.JumpToLabelIfZero(alternativeLabel))
 
-	resultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(resultHeapAddress)
+	consequence := t.transpileBranchValue(expression.Consequence, "conditional expression")
+	t.addInstruction(whitespace.JumpToLabel(endConditionalLabel))
 
-	return &object.Integer{HeapAddress: resultHeapAddress}
-}
+	t.addInstruction(whitespace.Label(alternativeLabel))
+	alternative := t.transpileBranchValue(expression.Alternative, "conditional expression")
 
-func (t *Transpiler) storeTopStackValueInHeapInstruction(heapAddress int64) {
-	t.pushNumberLiteralToStackInstruction(heapAddress)
-	t.addInstruction(whitespace.SwapTwoTopStackItems())
-	t.addInstruction(whitespace.StoreInHeap())
-}
+	t.addInstruction(whitespace.Label(endConditionalLabel))
 
-func (t *Transpiler) storeValueInHeapInstruction(heapAddress int64, value int64) {
-	t.pushNumberLiteralToStackInstruction(heapAddress)
-	t.pushNumberLiteralToStackInstruction(value)
-	t.addInstruction(whitespace.StoreInHeap())
-}
+	if !sameType(consequence, alternative) {
+		t.addError(expression, "conditional expression type mismatch %s : %s", describeType(consequence), describeType(alternative))
+	}
 
-func (t *Transpiler) retrieveFromHeapInstruction(heapAddress int64) {
-	t.pushNumberLiteralToStackInstruction(heapAddress)
-	t.addInstruction(whitespace.RetrieveFromHeap())
+	return branchesValue(consequence, alternative)
 }
 
-func (t *Transpiler) retrieveMultipleFromHeapInstruction(heapAddress1 int64, heapAddress2 int64) {
-	t.retrieveFromHeapInstruction(heapAddress1)
-	t.retrieveFromHeapInstruction(heapAddress2)
-	t.addInstruction(whitespace.LiftStackItem(1))
-	t.addInstruction(whitespace.SwapTwoTopStackItems())
-}
+// transpileLogicalExpression evaluates the right operand only when the left one does not decide the result, the same as Javascript.
+// The result is the value of the last evaluated operand.
+func (t *Transpiler) transpileLogicalExpression(expression *ast.InfixExpression) object.Object {
+	rightOperandLabel := t.getEmptyLabelId()
+	endLogicalLabel := t.getEmptyLabelId()
 
-func (t *Transpiler) pushNumberLiteralToStackInstruction(value int64) {
-	t.addInstruction(
-		whitespace.Instruction{
-			Body: append(
-				whitespace.PushToStack().Body,
-				whitespace.NumberLiteral(value).Body...,
-			),
-		},
-	)
-}
+	left := t.transpileBranchValue(expression.Left, "logical expression")
 
-func (t *Transpiler) printTopStackCharInstruction() {
-	t.addInstruction(whitespace.PrintTopStackChar())
-}
+	// The left operand stays on the stack as the result unless the right one is evaluated
+	t.addInstruction(whitespace.DuplicateTopStackItem())
+	t.pushTruthinessInstruction(left)
 
-func (t *Transpiler) printTopStackIntegerInstruction() {
-	t.addInstruction(whitespace.PrintTopStackInteger())
-}
+	if expression.Operator == ast.AND {
+		t.addInstruction(whitespace.JumpToLabelIfZero(endLogicalLabel))
+	} else {
+		t.addInstruction(whitespace.JumpToLabelIfZero(rightOperandLabel))
+		t.addInstruction(whitespace.JumpToLabel(endLogicalLabel))
+	}
 
-func (t *Transpiler) additionInstruction(heapAddress1 int64, heapAddress2 int64) {
-	t.retrieveMultipleFromHeapInstruction(heapAddress1, heapAddress2)
-	t.addInstruction(whitespace.Add())
-}
+	t.addInstruction(whitespace.Label(rightOperandLabel))
+	t.addInstruction(whitespace.DiscardTopStackItem())
+	right := t.transpileBranchValue(expression.Right, "logical expression")
 
-func (t *Transpiler) literalAdditionInstruction(heapAddress, value int64) {
-	t.retrieveFromHeapInstruction(heapAddress)
-	t.pushNumberLiteralToStackInstruction(value)
-	t.addInstruction(whitespace.Add())
-}
+	t.addInstruction(whitespace.Label(endLogicalLabel))
 
-func (t *Transpiler) subtractionInstruction(heapAddress1, heapAddress2 int64) {
-	t.retrieveMultipleFromHeapInstruction(heapAddress1, heapAddress2)
-	t.addInstruction(whitespace.Subtract())
-}
+	if !sameType(left, right) {
+		t.addError(expression, "type mismatch %s %s %s", describeType(left), expression.Operator, describeType(right))
+	}
 
-func (t *Transpiler) literalSubtractionInstruction(heapAddress, value int64) {
-	t.retrieveFromHeapInstruction(heapAddress)
-	t.pushNumberLiteralToStackInstruction(value)
-	t.addInstruction(whitespace.Subtract())
+	return branchesValue(left, right)
 }
 
-func (t *Transpiler) multiplicationInstruction(heapAddress1, heapAddress2 int64) {
-	t.retrieveMultipleFromHeapInstruction(heapAddress1, heapAddress2)
-	t.addInstruction(whitespace.Multiply())
-}
+// transpileBranchValue leaves the value of an expression on the stack, where other branches leave theirs
+func (t *Transpiler) transpileBranchValue(branch ast.Expression, description string) object.Object {
+	value := t.transpileValue(branch)
 
-func (t *Transpiler) literalMultiplicationInstruction(heapAddress, value int64) {
-	t.retrieveFromHeapInstruction(heapAddress)
-	t.pushNumberLiteralToStackInstruction(value)
-	t.addInstruction(whitespace.Multiply())
-}
+	switch value.(type) {
+	case *object.Integer, *object.Boolean, *object.String, *object.Array:
+	default:
+		t.addError(branch, "unsupported %s value %s", description, value.Type())
+	}
 
-func (t *Transpiler) literalStackMultiplicationInstruction(value int64) {
-	t.pushNumberLiteralToStackInstruction(value)
-	t.addInstruction(whitespace.Multiply())
+	return value
 }
 
-func (t *Transpiler) divisionInstruction(heapAddress1, heapAddress2 int64) {
-	t.retrieveMultipleFromHeapInstruction(heapAddress1, heapAddress2)
-	t.addInstruction(whitespace.Divide())
-}
+// branchesValue is the value left by either branch, arrays of unknown elements take the element type of the other branch
+func branchesValue(first, second object.Object) object.Object {
+	template := first
+	if array, ok := first.(*object.Array); ok && array.Element == nil {
+		template = second
+	}
 
-func (t *Transpiler) moduloInstruction(heapAddress1, heapAddress2 int64) {
-	t.retrieveMultipleFromHeapInstruction(heapAddress1, heapAddress2)
-	t.addInstruction(whitespace.Mod())
+	return stackValue(template)
 }
 
-func (t *Transpiler) comparisonInstruction(operator string, leftHeapAddress, rightHeapAddress int64) {
-	matchLabel := t.getEmptyLabelId()
-	endComparisonLabel := t.getEmptyLabelId()
-
-	if operator == ast.EQUALS || operator == ast.NOT_EQUALS || operator == ast.GREATER_THAN || operator == ast.LESS_THAN_OR_EQUAL {
-		t.subtractionInstruction(leftHeapAddress, rightHeapAddress)
-		t.addInstruction(whitespace.JumpToLabelIfZero(matchLabel))
+// transpileUpdateExpression stores the incremented or decremented operand back,
+// the result is the updated value for prefix operators and the previous one for suffix operators
+func (t *Transpiler) transpileUpdateExpression(node ast.Node, operand ast.Expression, operator string, prefix, keepResult bool) object.Object {
+	currentValue, store := t.transpileReference(operand)
+	if currentValue.Type() != object.INT_OBJ {
+		t.addError(node, "unsupported %s target %q", operator, currentValue.Type())
 	}
 
-	if operator == ast.LESS_THAN || operator == ast.GREATER_THAN || operator == ast.LESS_THAN_OR_EQUAL || operator == ast.GREATER_THAN_OR_EQUAL {
-		t.subtractionInstruction(leftHeapAddress, rightHeapAddress)
-		t.addInstruction(whitespace.JumpToLabelIfNegative(matchLabel))
+	if keepResult && !prefix {
+		t.addInstruction(whitespace.DuplicateTopStackItem())
 	}
 
+	t.pushNumberLiteralToStackInstruction(1)
+
 	switch operator {
-	case ast.EQUALS, ast.LESS_THAN, ast.LESS_THAN_OR_EQUAL:
-		t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
-	case ast.NOT_EQUALS, ast.GREATER_THAN, ast.GREATER_THAN_OR_EQUAL:
-		t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+	case ast.INCREMENT:
+		t.addInstruction(whitespace.Add())
+	case ast.DECREMENT:
+		t.addInstruction(whitespace.Subtract())
 	default:
-		panic(fmt.Sprintf("unknown operator %q", operator))
+		t.addError(node, "unknown operator %s", operator)
 	}
 
-	t.addInstruction(whitespace.JumpToLabel(endComparisonLabel))
+	if !keepResult {
+		store(node, &object.Integer{}, 0)
 
-	t.addInstruction(whitespace.Label(matchLabel))
+		return &object.Void{}
+	}
 
-	switch operator {
-	case ast.EQUALS, ast.LESS_THAN, ast.LESS_THAN_OR_EQUAL:
-		t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
-	case ast.NOT_EQUALS, ast.GREATER_THAN, ast.GREATER_THAN_OR_EQUAL:
-		t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
-	default:
-		panic(fmt.Sprintf("unknown operator %q", operator))
+	if prefix {
+		t.addInstruction(whitespace.DuplicateTopStackItem())
 	}
 
-	t.addInstruction(whitespace.Label(endComparisonLabel))
+	store(node, &object.Integer{}, 1)
+
+	return &object.Integer{}
 }
 
-func (t *Transpiler) andInstruction(leftHeapAddress, rightHeapAddress int64) {
-	firstMatchLabel := t.getEmptyLabelId()
-	secondMatchLabel := t.getEmptyLabelId()
-	endComparisonLabel := t.getEmptyLabelId()
+func (t *Transpiler) pushHeapAddressInstruction(heapAddress int64) {
+	if heapAddress >= 0 {
+		t.pushNumberLiteralToStackInstruction(heapAddress)
 
-	comparatorHeapAddress := t.getEmptyHeapAddress()
-	t.storeValueInHeapInstruction(comparatorHeapAddress, 0)
+		return
+	}
 
-	t.comparisonInstruction(ast.EQUALS, leftHeapAddress, comparatorHeapAddress)
-	t.addInstruction(whitespace.JumpToLabelIfZero(firstMatchLabel))
+	t.retrieveFromHeapInstruction(framePointerHeapAddress)
+	t.pushNumberLiteralToStackInstruction(-heapAddress)
+	t.addInstruction(whitespace.Add())
+}
 
-	t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
-	t.addInstruction(whitespace.JumpToLabel(endComparisonLabel))
+func (t *Transpiler) storeTopStackValueInHeapInstruction(heapAddress int64) {
+	t.pushHeapAddressInstruction(heapAddress)
+	t.addInstruction(whitespace.SwapTwoTopStackItems())
+	t.addInstruction(whitespace.StoreInHeap())
+}
 
-	t.addInstruction(whitespace.Label(firstMatchLabel))
+func (t *Transpiler) storeValueInHeapInstruction(heapAddress int64, value int64) {
+	t.pushHeapAddressInstruction(heapAddress)
+	t.pushNumberLiteralToStackInstruction(value)
+	t.addInstruction(whitespace.StoreInHeap())
+}
 
-	firstComparisonResultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(firstComparisonResultHeapAddress)
+func (t *Transpiler) retrieveFromHeapInstruction(heapAddress int64) {
+	t.pushHeapAddressInstruction(heapAddress)
+	t.addInstruction(whitespace.RetrieveFromHeap())
+}
 
-	t.comparisonInstruction(ast.EQUALS, rightHeapAddress, comparatorHeapAddress)
-	t.addInstruction(whitespace.JumpToLabelIfZero(secondMatchLabel))
+func (t *Transpiler) pushNumberLiteralToStackInstruction(value int64) {
+	t.addInstruction(whitespace.PushToStack(value))
+}
 
-	t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
-	t.addInstruction(whitespace.JumpToLabel(endComparisonLabel))
+func (t *Transpiler) printTopStackCharInstruction() {
+	t.addInstruction(whitespace.PrintTopStackChar())
+}
 
-	t.addInstruction(whitespace.Label(secondMatchLabel))
-	t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+func (t *Transpiler) printTopStackIntegerInstruction() {
+	t.addInstruction(whitespace.PrintTopStackInteger())
+}
 
-	t.addInstruction(whitespace.Label(endComparisonLabel))
+func (t *Transpiler) literalStackMultiplicationInstruction(value int64) {
+	t.pushNumberLiteralToStackInstruction(value)
+	t.addInstruction(whitespace.Multiply())
 }
 
-func (t *Transpiler) orInstruction(leftHeapAddress, rightHeapAddress int64) {
+// Stack: left, right -> boolean. Greater than is checked as the swapped less than, so every comparison takes one jump.
+func (t *Transpiler) comparisonInstruction(operator string) {
 	matchLabel := t.getEmptyLabelId()
 	endComparisonLabel := t.getEmptyLabelId()
 
-	comparatorHeapAddress := t.getEmptyHeapAddress()
-	t.storeValueInHeapInstruction(comparatorHeapAddress, 0)
+	if operator == ast.GREATER_THAN || operator == ast.LESS_THAN_OR_EQUAL {
+		t.addInstruction(whitespace.SwapTwoTopStackItems())
+	}
 
-	t.comparisonInstruction(ast.EQUALS, leftHeapAddress, comparatorHeapAddress)
-	t.addInstruction(whitespace.JumpToLabelIfZero(matchLabel))
+	t.addInstruction(whitespace.Subtract())
 
-	firstComparisonResultHeapAddress := t.getEmptyHeapAddress()
-	t.storeTopStackValueInHeapInstruction(firstComparisonResultHeapAddress)
+	if operator == ast.EQUALS || operator == ast.NOT_EQUALS {
+		t.addInstruction(whitespace.JumpToLabelIfZero(matchLabel))
+	} else {
+		t.addInstruction(whitespace.JumpToLabelIfNegative(matchLabel))
+	}
 
-	t.comparisonInstruction(ast.EQUALS, rightHeapAddress, comparatorHeapAddress)
-	t.addInstruction(whitespace.JumpToLabelIfZero(matchLabel))
+	// Equality and strict orderings hold on a match, the others hold otherwise
+	matchValue := int64(whitespace.TRUE)
+	if operator == ast.NOT_EQUALS || operator == ast.LESS_THAN_OR_EQUAL || operator == ast.GREATER_THAN_OR_EQUAL {
+		matchValue = whitespace.FALSE
+	}
 
-	t.pushNumberLiteralToStackInstruction(whitespace.FALSE)
+	t.pushNumberLiteralToStackInstruction(1 - matchValue)
 	t.addInstruction(whitespace.JumpToLabel(endComparisonLabel))
 
 	t.addInstruction(whitespace.Label(matchLabel))
-	t.pushNumberLiteralToStackInstruction(whitespace.TRUE)
+	t.pushNumberLiteralToStackInstruction(matchValue)
 
 	t.addInstruction(whitespace.Label(endComparisonLabel))
 }
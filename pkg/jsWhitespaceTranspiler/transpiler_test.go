@@ -20,9 +20,9 @@ let expression = (number1 + 2) / 2 > 1000;
 expression === true;
 
 if (false) {
-	expression = 1;
+	expression = false;
 } else {
-	expression = 2;
+	expression = !expression;
 }
 
 for (let i = 0; i < 10; i++) {
@@ -33,366 +33,551 @@ for (let i = 0; i < 10; i++) {
 `
 
 	expectedInstructions := []whitespace.Instruction{
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.LINE_FEED, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.SPACE}},
-		{Body: []whitespace.Token{whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.TAB, whitespace.TAB, whitespace.TAB}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.SPACE, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.LINE_FEED, whitespace.SPACE, whitespace.TAB, whitespace.TAB, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.SPACE, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.SPACE, whitespace.SPACE, whitespace.TAB, whitespace.LINE_FEED}},
-		{Body: []whitespace.Token{whitespace.LINE_FEED, whitespace.LINE_FEED, whitespace.LINE_FEED}},
+		whitespace.PushToStack(1),
+		whitespace.PushToStack(5),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(2),
+		whitespace.PushToStack(118),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(3),
+		whitespace.PushToStack(97),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(4),
+		whitespace.PushToStack(108),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(5),
+		whitespace.PushToStack(117),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(6),
+		whitespace.PushToStack(101),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(42),
+		whitespace.PushToStack(72),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(101),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(108),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(108),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(111),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(32),
+		whitespace.PrintTopStackChar(),
+		whitespace.PrintTopStackInteger(),
+		whitespace.PushToStack(10),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(1),
+		whitespace.PushToStack(7),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(1337),
+		whitespace.PushToStack(8),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(8),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(7),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(8),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.LiftStackItem(2),
+		whitespace.CallSubroutine(1),
+		whitespace.PushToStack(32),
+		whitespace.PrintTopStackChar(),
+		whitespace.LiftStackItem(1),
+		whitespace.PrintTopStackInteger(),
+		whitespace.PushToStack(32),
+		whitespace.PrintTopStackChar(),
+		whitespace.PrintTopStackInteger(),
+		whitespace.DiscardTopStackItem(),
+		whitespace.DiscardTopStackItem(),
+		whitespace.PushToStack(10),
+		whitespace.PrintTopStackChar(),
+		whitespace.PushToStack(8),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(2),
+		whitespace.Add(),
+		whitespace.PushToStack(2),
+		whitespace.Divide(),
+		whitespace.PushToStack(1000),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfNegative(2),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(3),
+		whitespace.Label(2),
+		whitespace.PushToStack(1),
+		whitespace.Label(3),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(1),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfZero(4),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(5),
+		whitespace.Label(4),
+		whitespace.PushToStack(1),
+		whitespace.Label(5),
+		whitespace.DiscardTopStackItem(),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabelIfZero(6),
+		whitespace.PushToStack(0),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.JumpToLabel(7),
+		whitespace.Label(6),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.JumpToLabelIfZero(8),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(9),
+		whitespace.Label(8),
+		whitespace.PushToStack(1),
+		whitespace.Label(9),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.Label(7),
+		whitespace.PushToStack(0),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(10),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfNegative(13),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(14),
+		whitespace.Label(13),
+		whitespace.PushToStack(1),
+		whitespace.Label(14),
+		whitespace.JumpToLabelIfZero(12),
+		whitespace.JumpToLabel(11),
+		whitespace.Label(10),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(1),
+		whitespace.Add(),
+		whitespace.PushToStack(9),
+		whitespace.SwapTwoTopStackItems(),
+		whitespace.StoreInHeap(),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(10),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfNegative(15),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(16),
+		whitespace.Label(15),
+		whitespace.PushToStack(1),
+		whitespace.Label(16),
+		whitespace.JumpToLabelIfZero(12),
+		whitespace.Label(11),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(2),
+		whitespace.Mod(),
+		whitespace.PushToStack(0),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfZero(21),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(22),
+		whitespace.Label(21),
+		whitespace.PushToStack(1),
+		whitespace.Label(22),
+		whitespace.DuplicateTopStackItem(),
+		whitespace.JumpToLabelIfZero(19),
+		whitespace.JumpToLabel(20),
+		whitespace.Label(19),
+		whitespace.DiscardTopStackItem(),
+		whitespace.PushToStack(9),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(8),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfZero(23),
+		whitespace.PushToStack(0),
+		whitespace.JumpToLabel(24),
+		whitespace.Label(23),
+		whitespace.PushToStack(1),
+		whitespace.Label(24),
+		whitespace.Label(20),
+		whitespace.JumpToLabelIfZero(17),
+		whitespace.JumpToLabel(10),
+		whitespace.JumpToLabel(18),
+		whitespace.Label(17),
+		whitespace.Label(18),
+		whitespace.JumpToLabel(10),
+		whitespace.Label(12),
+		whitespace.EndProgram(),
+		whitespace.Label(1),
+		whitespace.DuplicateTopStackItem(),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PushToStack(0),
+		whitespace.Label(25),
+		whitespace.DuplicateTopStackItem(),
+		whitespace.LiftStackItem(2),
+		whitespace.Subtract(),
+		whitespace.JumpToLabelIfZero(26),
+		whitespace.PushToStack(1),
+		whitespace.Add(),
+		whitespace.LiftStackItem(2),
+		whitespace.LiftStackItem(1),
+		whitespace.Add(),
+		whitespace.RetrieveFromHeap(),
+		whitespace.PrintTopStackChar(),
+		whitespace.JumpToLabel(25),
+		whitespace.Label(26),
+		whitespace.DiscardTopStackItem(),
+		whitespace.DiscardTopStackItem(),
+		whitespace.DiscardTopStackItem(),
+		whitespace.EndSubroutine(),
 	}
 
 	lexer := NewLexer(strings.NewReader(input))
-	parsedAst := NewParser(lexer).ParseProgram()
+	parsedAst, err := NewParser(lexer).ParseProgram()
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	whitespaceProgram, err := NewTranspiler().TranspileProgram(parsedAst)
+	if err != nil {
+		t.Fatalf("cannot transpile source, error: %v", err)
+	}
+
+	instructions := whitespaceProgram.Instructions()
+
+	if len(instructions) != len(expectedInstructions) {
+		t.Fatalf("instruction count incorrect. expected=%d, got=%d", len(expectedInstructions), len(instructions))
+	}
 
-	whitespaceProgram := NewTranspiler().TranspileProgram(parsedAst)
+	for i, instruction := range instructions {
+		if instruction != expectedInstructions[i] {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i+1, expectedInstructions[i], instruction)
+		}
+	}
+}
 
-	for i, instruction := range whitespaceProgram.Instructions() {
-		currentInstruction := instruction.String()
-		expectedInstruction := expectedInstructions[i].String()
+func TestTranspilerErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedErrors []string
+	}{
+		{
+			name: "variables",
+			input: `let number = 1;
+let number = 2;
+console.log(undefinedVariable);
+if (number) {
+	number = "text";
+	console.log(number - "text");
+}
+break;
+let flag = number > 0;
+flag = 1;
+console.log(flag + true);
+let fallback = number || "none";`,
+			expectedErrors: []string{
+				"test.js:2:5: error: redeclaration of number",
+				"test.js:3:13: error: undefinedVariable is not defined",
+				"test.js:5:2: error: assignment type mismatch INT = STRING",
+				"test.js:6:14: error: type mismatch INT - STRING",
+				"test.js:8:1: error: cannot determine break target",
+				"test.js:10:1: error: assignment type mismatch BOOLEAN = INT",
+				"test.js:11:13: error: unknown operator BOOLEAN + BOOLEAN",
+				"test.js:12:16: error: type mismatch INT || STRING",
+			},
+		},
+		{
+			name: "functions",
+			input: `function add(a, b) {
+	return a + b;
+}
+add(1);
+function add() {}
+return 1;
+if (true) {
+	function nested() {}
+}
+function duplicate(a, a) {}
+function text() {
+	return "text";
+}
+function either(flag) {
+	if (flag) {
+		return true;
+	}
+	return 1;
+}
+either(false);
+either(0);`,
+			expectedErrors: []string{
+				"test.js:4:1: error: add expects 2 argument(s), got 1",
+				"test.js:5:10: error: redeclaration of add",
+				"test.js:6:1: error: return outside of function",
+				"test.js:8:2: error: function declarations are only supported at the top level",
+				"test.js:10:23: error: duplicate parameter a",
+				"test.js:12:9: error: unsupported return value STRING",
+				"test.js:18:9: error: return type mismatch BOOLEAN, INT",
+				"test.js:21:8: error: argument type mismatch BOOLEAN, INT",
+			},
+		},
+		{
+			name: "labels",
+			input: `outer: for (let i = 0; i < 3; i++) {
+	break inner;
+}
+block: {
+	continue block;
+}
+loop: while (true) {
+	loop: while (true) {
+		break loop;
+	}
+}
+continue;`,
+			expectedErrors: []string{
+				"test.js:2:8: error: undefined label inner",
+				"test.js:5:11: error: label block does not denote a loop",
+				"test.js:8:2: error: label loop has already been declared",
+				"test.js:12:1: error: cannot determine continue target",
+			},
+		},
+		{
+			name: "arrays",
+			input: `let numbers = [1, "two"];
+let words = ["one"];
+words.push(2);
+words[0] = 3;
+let number = 1;
+number[0] = 1;
+console.log(words.size);
+words.push();
+words = [1];`,
+			expectedErrors: []string{
+				"test.js:1:19: error: array element type mismatch INT, STRING",
+				"test.js:3:1: error: array element type mismatch STRING, INT",
+				"test.js:4:12: error: array element type mismatch STRING, INT",
+				"test.js:6:1: error: cannot index INT",
+				"test.js:7:19: error: property size does not exist on ARRAY",
+				"test.js:8:1: error: push expects 1 argument(s), got 0",
+				"test.js:9:1: error: assignment type mismatch ARRAY<STRING> = ARRAY<INT>",
+			},
+		},
+		{
+			name: "scopes",
+			input: `const a = 1;
+a = 2;
+if (a === 1) {
+	let b = 1;
+	let b = 2;
+}
+b = 3;
+for (const i = 0; i < 3; i++) {
+}
+const c = [1];
+c[0] = 2;`,
+			expectedErrors: []string{
+				"test.js:2:1: error: assignment to constant variable a",
+				"test.js:5:6: error: redeclaration of b",
+				"test.js:7:1: error: b is not defined",
+				"test.js:8:26: error: assignment to constant variable i",
+			},
+		},
+		{
+			name: "conditionals",
+			input: `let a = 1 > 0 ? 1 : "one";
+let b = console.log() ? 1 : 0;
+let c = [] ? console.log(1) : 0;`,
+			expectedErrors: []string{
+				"test.js:1:9: error: conditional expression type mismatch INT : STRING",
+				"test.js:2:9: error: invalid conditional expression condition",
+				"test.js:3:14: error: unsupported conditional expression value VOID",
+			},
+		},
+		{
+			name: "updates",
+			input: `const limit = 1;
+limit += 1;
+limit++;
+let text = "a";
+text -= "b";
+text++;
+let flags = [true];
+flags[0] += 1;`,
+			expectedErrors: []string{
+				"test.js:2:1: error: assignment to constant variable limit",
+				"test.js:3:1: error: assignment to constant variable limit",
+				"test.js:5:1: error: unknown operator STRING - STRING",
+				"test.js:6:1: error: unsupported ++ target \"STRING\"",
+				"test.js:8:1: error: type mismatch BOOLEAN + INT",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedAst, err := NewParser(NewNamedLexer(strings.NewReader(test.input), "test.js")).ParseProgram()
+			if err != nil {
+				t.Fatalf("cannot parse source, error: %v", err)
+			}
+
+			_, err = NewTranspiler().TranspileProgram(parsedAst)
+			if err == nil {
+				t.Fatalf("expected transpilation errors")
+			}
+
+			if err.Error() != strings.Join(test.expectedErrors, "\n") {
+				t.Fatalf("errors incorrect. expected=\n%s\ngot=\n%s", strings.Join(test.expectedErrors, "\n"), err)
+			}
+		})
+	}
+}
+
+func TestTranspilerArrayBoundsCheck(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{`let a = [1, 2]; console.log(a[1]); console.log(a[2]); console.log(3);`, "2\nRangeError: array index out of bounds\n"},
+		{`let a = [1, 2]; a[-1] = 5; console.log(3);`, "RangeError: array index out of bounds\n"},
+		{`let a = []; a.push(4); a[0] = a[0] + 1; console.log(a[0], a.length);`, "5 1\n"},
+	}
+
+	for _, test := range tests {
+		output, err := runTranspiled(test.input, "")
+		if err != nil {
+			t.Fatalf("cannot run %q, error: %v", test.input, err)
+		}
+
+		if output != test.expectedOutput {
+			t.Errorf("output of %q incorrect. expected=%q, got=%q", test.input, test.expectedOutput, output)
+		}
+	}
+}
+
+func TestTranspilerInput(t *testing.T) {
+	tests := []struct {
+		input          string
+		stdin          string
+		expectedOutput string
+	}{
+		{`let name = readline(); console.log("Hello " + name + "!");`, "World\n", "Hello World!\n"},
+		{`let a = readInt(); let b = readInt(); console.log(a * b);`, "6\n-7\n", "-42\n"},
+		{`let count = readInt(); let line = readline(); console.log(count, line);`, "2\nzażółć", "2 zażółć\n"},
+		{`let first = readline(); let second = readline(); console.log(!first, !second, "|" + first + second + "|");`, "\n", "true true ||\n"},
+		{`let line = readline(); console.log("|" + line + "|", !line);`, "", "|| true\n"},
+	}
 
-		if currentInstruction != expectedInstruction {
-			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i+1, expectedInstruction, currentInstruction)
+	for _, test := range tests {
+		output, err := runTranspiled(test.input, test.stdin)
+		if err != nil {
+			t.Fatalf("cannot run %q, error: %v", test.input, err)
 		}
+
+		if output != test.expectedOutput {
+			t.Errorf("output of %q incorrect. expected=%q, got=%q", test.input, test.expectedOutput, output)
+		}
+	}
+}
+
+func TestTranspilerStackExpressions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedStores int
+	}{
+		{`console.log(1 + 2 * 3 - -4 % 5, 7 / 2 > 3, !(1 === 2) || false);`, 0},
+		{`let a = 1; let b = (a + 2) * (a - 3) % 4; console.log(b > a ? -b : a, !b && a <= b);`, 2},
+		{`let i = 0; i++; ++i; i += i-- * 2; console.log(i);`, 5},
+	}
+
+	for _, test := range tests {
+		parsedSource, err := parseSource(test.input)
+		if err != nil {
+			t.Fatalf("cannot parse %q, error: %v", test.input, err)
+		}
+
+		program, err := NewTranspiler().TranspileProgram(parsedSource)
+		if err != nil {
+			t.Fatalf("cannot transpile %q, error: %v", test.input, err)
+		}
+
+		stores := 0
+
+		for _, instruction := range program.Instructions() {
+			if instruction.Opcode == whitespace.STORE {
+				stores++
+			}
+		}
+
+		if stores != test.expectedStores {
+			t.Errorf("heap stores of %q incorrect. expected=%d, got=%d", test.input, test.expectedStores, stores)
+		}
+	}
+}
+
+func TestTranspilerHeapUsage(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedHeapUsage HeapUsage
+	}{
+		{`let a = 1; console.log(a); let b = 2; console.log(b); let c = 3; console.log(c);`, HeapUsage{GlobalHeapAddresses: 1}},
+		{`let a = 1; let b = 2; console.log(a + b); let c = a;`, HeapUsage{GlobalHeapAddresses: 2}},
+		{`for (let i = 0; i < 2; i++) { let j = i; console.log(j); } let k = 1;`, HeapUsage{GlobalHeapAddresses: 2}},
+		{`if (true) { let a = 1; console.log(a); } let b = 2; console.log(b);`, HeapUsage{GlobalHeapAddresses: 1}},
+		{
+			`let g = 1; function f(n) { let m = n + g; console.log(m); let k = 2; return k; } let h = f(1); console.log(h);`,
+			HeapUsage{GlobalHeapAddresses: 2, LargestFrameSize: 3},
+		},
+	}
+
+	for _, test := range tests {
+		parsedSource, err := parseSource(test.input)
+		if err != nil {
+			t.Fatalf("cannot parse %q, error: %v", test.input, err)
+		}
+
+		transpiler := NewTranspiler()
+
+		if _, err = transpiler.TranspileProgram(parsedSource); err != nil {
+			t.Fatalf("cannot transpile %q, error: %v", test.input, err)
+		}
+
+		if transpiler.HeapUsage() != test.expectedHeapUsage {
+			t.Errorf("heap usage of %q incorrect. expected=%+v, got=%+v", test.input, test.expectedHeapUsage, transpiler.HeapUsage())
+		}
+	}
+}
+
+func TestTranspilerLetCopiesIntegerVariable(t *testing.T) {
+	output, err := runTranspiled(`let a = 1; let b = a; b = 2; console.log(a, b); let c = b; c = c + 10; console.log(b, c);`, "")
+	if err != nil {
+		t.Fatalf("cannot run program, error: %v", err)
+	}
+
+	if output != "1 2\n2 12\n" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "1 2\n2 12\n", output)
+	}
+}
+
+func TestTranspilerPrefixOperatorsKeepOperand(t *testing.T) {
+	output, err := runTranspiled(`let a = 2; console.log(a, -a, a); let b = 0; console.log(!b, b);`, "")
+	if err != nil {
+		t.Fatalf("cannot run program, error: %v", err)
+	}
+
+	if output != "2 -2 2\ntrue 0\n" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "2 -2 2\ntrue 0\n", output)
 	}
 }
@@ -0,0 +1,208 @@
+package whitespace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var numericLabelPattern = regexp.MustCompile(`^L(0|[1-9][0-9]*)$`)
+
+type assembler struct {
+	instructions []Instruction
+	labels       []assemblyLabel
+}
+
+type assemblyLabel struct {
+	name             string
+	lineNumber       int
+	instructionIndex int
+}
+
+func Assemble(source io.Reader) ([]Instruction, error) {
+	a := &assembler{}
+
+	scanner := bufio.NewScanner(source)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		if err := a.assembleLine(scanner.Text(), lineNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read assembly source, error: %v", err)
+	}
+
+	if err := a.resolveLabels(); err != nil {
+		return nil, err
+	}
+
+	return a.instructions, nil
+}
+
+func (a *assembler) assembleLine(line string, lineNumber int) error {
+	fields := splitAssemblyLine(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// Leading byte offsets are skipped, so disassembler listings can be assembled back
+	if _, err := strconv.Atoi(fields[0]); err == nil && len(fields) > 1 {
+		fields = fields[1:]
+	}
+
+	if len(fields) == 1 && strings.HasSuffix(fields[0], ":") {
+		fields = []string{string(LABEL), strings.TrimSuffix(fields[0], ":")}
+	}
+
+	encoding, ok := lookupOpcodeEncoding(Opcode(strings.ToLower(fields[0])))
+	if !ok {
+		return fmt.Errorf("[:%d] unknown instruction %q", lineNumber, fields[0])
+	}
+
+	expectedFieldCount := 1
+	if encoding.argument != noArgument {
+		expectedFieldCount = 2
+	}
+
+	if len(fields) != expectedFieldCount {
+		return fmt.Errorf("[:%d] %s expects %d argument(s), got %d", lineNumber, encoding.opcode, expectedFieldCount-1, len(fields)-1)
+	}
+
+	instruction := Instruction{Opcode: encoding.opcode}
+
+	switch encoding.argument {
+	case numberArgument:
+		value, err := parseAssemblyNumber(fields[1])
+		if err != nil {
+			return fmt.Errorf("[:%d] %v", lineNumber, err)
+		}
+
+		instruction.Argument = value
+	case labelArgument:
+		a.labels = append(a.labels, assemblyLabel{
+			name:             fields[1],
+			lineNumber:       lineNumber,
+			instructionIndex: len(a.instructions),
+		})
+	}
+
+	a.instructions = append(a.instructions, instruction)
+
+	return nil
+}
+
+func (a *assembler) resolveLabels() error {
+	labelIds := make(map[string]int64)
+	definedLabels := make(map[string]bool)
+
+	var nextLabelId int64
+
+	for _, label := range a.labels {
+		if match := numericLabelPattern.FindStringSubmatch(label.name); match != nil {
+			// The largest id is rejected, as the ids given to symbolic labels after it would overflow
+			labelId, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil || labelId == math.MaxInt64 {
+				return fmt.Errorf("[:%d] invalid label %q", label.lineNumber, label.name)
+			}
+
+			labelIds[label.name] = labelId
+			nextLabelId = max(nextLabelId, labelId+1)
+		}
+	}
+
+	for _, label := range a.labels {
+		if a.instructions[label.instructionIndex].Opcode != LABEL {
+			continue
+		}
+
+		if definedLabels[label.name] {
+			return fmt.Errorf("[:%d] duplicate label %q", label.lineNumber, label.name)
+		}
+
+		definedLabels[label.name] = true
+	}
+
+	for _, label := range a.labels {
+		if !definedLabels[label.name] {
+			return fmt.Errorf("[:%d] undefined label %q", label.lineNumber, label.name)
+		}
+
+		labelId, ok := labelIds[label.name]
+		if !ok {
+			labelId = nextLabelId
+			labelIds[label.name] = labelId
+			nextLabelId++
+		}
+
+		a.instructions[label.instructionIndex].Argument = labelId
+	}
+
+	return nil
+}
+
+func splitAssemblyLine(line string) []string {
+	var fields []string
+
+	for len(line) > 0 {
+		line = strings.TrimLeft(line, " \t\r")
+
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			break
+		}
+
+		fieldLength := strings.IndexAny(line, " \t\r;#")
+
+		if line[0] == '\'' {
+			fieldLength = characterLiteralLength(line)
+		}
+
+		if fieldLength == -1 {
+			fieldLength = len(line)
+		}
+
+		fields = append(fields, line[:fieldLength])
+		line = line[fieldLength:]
+	}
+
+	return fields
+}
+
+func characterLiteralLength(line string) int {
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '\'':
+			return i + 1
+		}
+	}
+
+	return len(line)
+}
+
+func parseAssemblyNumber(literal string) (int64, error) {
+	if strings.HasPrefix(literal, "'") {
+		value, _, tail, err := strconv.UnquoteChar(strings.TrimSuffix(literal[1:], "'"), '\'')
+		if err != nil || tail != "" || !strings.HasSuffix(literal, "'") || len(literal) < 3 {
+			return 0, fmt.Errorf("invalid character literal %s", literal)
+		}
+
+		return int64(value), nil
+	}
+
+	value, err := strconv.ParseInt(literal, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number literal %q", literal)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,127 @@
+package whitespace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAssembler(t *testing.T) {
+	source := `
+; print characters from 'a' to 'e'
+    push 'a'
+loop:
+    dup
+    outc
+    push 1
+    add
+    dup
+    push 'f'
+    sub
+    jz end          # stop after 'e'
+    jmp loop
+label end
+    push '\n'
+    outc
+    push 0x10
+    push -3
+    slide 1
+    outn
+    end
+`
+
+	expectedInstructions := []Instruction{
+		PushToStack('a'),
+		Label(0),
+		DuplicateTopStackItem(),
+		PrintTopStackChar(),
+		PushToStack(1),
+		Add(),
+		DuplicateTopStackItem(),
+		PushToStack('f'),
+		Subtract(),
+		JumpToLabelIfZero(1),
+		JumpToLabel(0),
+		Label(1),
+		PushToStack('\n'),
+		PrintTopStackChar(),
+		PushToStack(16),
+		PushToStack(-3),
+		SlideStackItems(1),
+		PrintTopStackInteger(),
+		EndProgram(),
+	}
+
+	instructions, err := Assemble(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("cannot assemble source, error: %v", err)
+	}
+
+	if len(instructions) != len(expectedInstructions) {
+		t.Fatalf("instruction count incorrect. expected=%d, got=%d", len(expectedInstructions), len(instructions))
+	}
+
+	for i, instruction := range instructions {
+		if instruction != expectedInstructions[i] {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i+1, expectedInstructions[i], instruction)
+		}
+	}
+
+	var output bytes.Buffer
+
+	vm, err := NewVirtualMachine(instructions, strings.NewReader(""), &output)
+	if err != nil {
+		t.Fatalf("cannot load program, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("runtime error: %v", err)
+	}
+
+	if output.String() != "abcde\n-3" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "abcde\n-3", output.String())
+	}
+}
+
+func TestAssemblerDisassemblyRoundTrip(t *testing.T) {
+	source := fromNotation("SS STSTL LSS STTL SS STTSTSSL TLSS LST STTL LLL")
+
+	var listing bytes.Buffer
+
+	if err := Disassemble(strings.NewReader(source), &listing); err != nil {
+		t.Fatalf("cannot disassemble source, error: %v", err)
+	}
+
+	instructions, err := Assemble(&listing)
+	if err != nil {
+		t.Fatalf("cannot assemble listing, error: %v", err)
+	}
+
+	reassembledSource := string(Encode(instructions))
+
+	if reassembledSource != source {
+		t.Errorf("round trip incorrect. expected=%q, got=%q", source, reassembledSource)
+	}
+}
+
+func TestAssemblerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "unknown instruction", source: "push 1\nfoo"},
+		{name: "missing argument", source: "push"},
+		{name: "unexpected argument", source: "add 1"},
+		{name: "invalid number", source: "push 12a"},
+		{name: "invalid character", source: "push 'ab'"},
+		{name: "undefined label", source: "jmp nowhere"},
+		{name: "duplicate label", source: "start:\nstart:"},
+		{name: "label id overflow", source: "L9223372036854775807:\nnext:\njmp next"},
+	}
+
+	for _, test := range tests {
+		if _, err := Assemble(strings.NewReader(test.source)); err == nil {
+			t.Errorf("%s: expected assembly error", test.name)
+		}
+	}
+}
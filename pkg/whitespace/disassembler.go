@@ -0,0 +1,26 @@
+package whitespace
+
+import (
+	"fmt"
+	"io"
+)
+
+func Disassemble(source io.Reader, output io.Writer) error {
+	parsedInstructions, err := ParseWithOffsets(source)
+	if err != nil {
+		return err
+	}
+
+	for _, parsedInstruction := range parsedInstructions {
+		indentation := "    "
+		if parsedInstruction.Instruction.Opcode == LABEL {
+			indentation = ""
+		}
+
+		if _, err = fmt.Fprintf(output, "%6d  %s%s\n", parsedInstruction.Offset, indentation, parsedInstruction.Instruction); err != nil {
+			return fmt.Errorf("cannot write disassembly, error: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package whitespace
 
 import (
+	"fmt"
 	"strconv"
 )
 
@@ -15,154 +16,278 @@ const (
 	//SPACE     = 'S'
 )
 
-type Instruction struct {
-	Body []Token
+type Opcode string
+
+const (
+	PUSH             Opcode = "push"
+	DUPLICATE        Opcode = "dup"
+	COPY             Opcode = "copy"
+	SWAP             Opcode = "swap"
+	DISCARD          Opcode = "drop"
+	SLIDE            Opcode = "slide"
+	ADD              Opcode = "add"
+	SUBTRACT         Opcode = "sub"
+	MULTIPLY         Opcode = "mul"
+	DIVIDE           Opcode = "div"
+	MOD              Opcode = "mod"
+	STORE            Opcode = "store"
+	RETRIEVE         Opcode = "retrieve"
+	LABEL            Opcode = "label"
+	CALL             Opcode = "call"
+	JUMP             Opcode = "jmp"
+	JUMP_IF_ZERO     Opcode = "jz"
+	JUMP_IF_NEGATIVE Opcode = "jn"
+	RETURN           Opcode = "ret"
+	END              Opcode = "end"
+	PRINT_CHAR       Opcode = "outc"
+	PRINT_NUMBER     Opcode = "outn"
+	READ_CHAR        Opcode = "inc"
+	READ_NUMBER      Opcode = "inn"
+)
+
+type argumentKind int
+
+const (
+	noArgument argumentKind = iota
+	numberArgument
+	labelArgument
+)
+
+type opcodeEncoding struct {
+	opcode   Opcode
+	tokens   []Token
+	argument argumentKind
 }
 
-func (i *Instruction) String() string {
-	return string(i.Body)
+var opcodeEncodings = []opcodeEncoding{
+	{opcode: PUSH, tokens: []Token{SPACE, SPACE}, argument: numberArgument},
+	{opcode: DUPLICATE, tokens: []Token{SPACE, LINE_FEED, SPACE}, argument: noArgument},
+	{opcode: COPY, tokens: []Token{SPACE, TAB, SPACE}, argument: numberArgument},
+	{opcode: SWAP, tokens: []Token{SPACE, LINE_FEED, TAB}, argument: noArgument},
+	{opcode: DISCARD, tokens: []Token{SPACE, LINE_FEED, LINE_FEED}, argument: noArgument},
+	{opcode: SLIDE, tokens: []Token{SPACE, TAB, LINE_FEED}, argument: numberArgument},
+	{opcode: ADD, tokens: []Token{TAB, SPACE, SPACE, SPACE}, argument: noArgument},
+	{opcode: SUBTRACT, tokens: []Token{TAB, SPACE, SPACE, TAB}, argument: noArgument},
+	{opcode: MULTIPLY, tokens: []Token{TAB, SPACE, SPACE, LINE_FEED}, argument: noArgument},
+	{opcode: DIVIDE, tokens: []Token{TAB, SPACE, TAB, SPACE}, argument: noArgument},
+	{opcode: MOD, tokens: []Token{TAB, SPACE, TAB, TAB}, argument: noArgument},
+	{opcode: STORE, tokens: []Token{TAB, TAB, SPACE}, argument: noArgument},
+	{opcode: RETRIEVE, tokens: []Token{TAB, TAB, TAB}, argument: noArgument},
+	{opcode: LABEL, tokens: []Token{LINE_FEED, SPACE, SPACE}, argument: labelArgument},
+	{opcode: CALL, tokens: []Token{LINE_FEED, SPACE, TAB}, argument: labelArgument},
+	{opcode: JUMP, tokens: []Token{LINE_FEED, SPACE, LINE_FEED}, argument: labelArgument},
+	{opcode: JUMP_IF_ZERO, tokens: []Token{LINE_FEED, TAB, SPACE}, argument: labelArgument},
+	{opcode: JUMP_IF_NEGATIVE, tokens: []Token{LINE_FEED, TAB, TAB}, argument: labelArgument},
+	{opcode: RETURN, tokens: []Token{LINE_FEED, TAB, LINE_FEED}, argument: noArgument},
+	{opcode: END, tokens: []Token{LINE_FEED, LINE_FEED, LINE_FEED}, argument: noArgument},
+	{opcode: PRINT_CHAR, tokens: []Token{TAB, LINE_FEED, SPACE, SPACE}, argument: noArgument},
+	{opcode: PRINT_NUMBER, tokens: []Token{TAB, LINE_FEED, SPACE, TAB}, argument: noArgument},
+	{opcode: READ_CHAR, tokens: []Token{TAB, LINE_FEED, TAB, SPACE}, argument: noArgument},
+	{opcode: READ_NUMBER, tokens: []Token{TAB, LINE_FEED, TAB, TAB}, argument: noArgument},
 }
 
-func Noop() Instruction {
-	return Instruction{
-		Body: []Token{SPACE, TAB, LINE_FEED, SPACE, LINE_FEED},
+func lookupOpcodeEncoding(opcode Opcode) (opcodeEncoding, bool) {
+	for _, encoding := range opcodeEncodings {
+		if encoding.opcode == opcode {
+			return encoding, true
+		}
 	}
+
+	return opcodeEncoding{}, false
 }
 
-func StoreInHeap() Instruction {
-	return Instruction{
-		Body: []Token{TAB, TAB, SPACE},
-	}
+type Instruction struct {
+	Opcode   Opcode
+	Argument int64
+	// CompactLabel encodes the label argument with compactLabelLiteral instead of NumberLiteral
+	CompactLabel bool
 }
 
-func RetrieveFromHeap() Instruction {
-	return Instruction{
-		Body: []Token{TAB, TAB, TAB},
+func (i Instruction) Encode() []Token {
+	encoding, ok := lookupOpcodeEncoding(i.Opcode)
+	if !ok {
+		panic(fmt.Sprintf("unknown opcode %q", i.Opcode))
 	}
-}
 
-func PushToStack() Instruction {
-	return Instruction{
-		Body: []Token{SPACE, SPACE},
+	tokens := append([]Token{}, encoding.tokens...)
+
+	switch {
+	case encoding.argument == labelArgument && i.CompactLabel:
+		tokens = append(tokens, compactLabelLiteral(i.Argument)...)
+	case encoding.argument != noArgument:
+		tokens = append(tokens, NumberLiteral(i.Argument)...)
 	}
+
+	return tokens
 }
 
-func SwapTwoTopStackItems() Instruction {
-	return Instruction{
-		Body: []Token{SPACE, LINE_FEED, TAB},
-	}
+func (i Instruction) HasArgument() bool {
+	encoding, ok := lookupOpcodeEncoding(i.Opcode)
+
+	return ok && encoding.argument != noArgument
 }
 
-func LiftStackItem(itemOrdinalNumber int) Instruction {
-	itemOrdinalNumberLiteral := NumberLiteral(int64(itemOrdinalNumber))
+func (i Instruction) HasLabelArgument() bool {
+	encoding, ok := lookupOpcodeEncoding(i.Opcode)
+
+	return ok && encoding.argument == labelArgument
+}
+
+func (i Instruction) String() string {
+	encoding, ok := lookupOpcodeEncoding(i.Opcode)
+	if !ok {
+		return fmt.Sprintf("unknown(%q)", i.Opcode)
+	}
 
-	return Instruction{
-		Body: append([]Token{SPACE, TAB, SPACE}, itemOrdinalNumberLiteral.Body...),
+	switch encoding.argument {
+	case numberArgument:
+		return fmt.Sprintf("%s %d", i.Opcode, i.Argument)
+	case labelArgument:
+		return fmt.Sprintf("%s L%d", i.Opcode, i.Argument)
+	default:
+		return string(i.Opcode)
 	}
 }
 
-func NumberLiteral(value int64) Instruction {
-	var instruction Instruction
+func NumberLiteral(value int64) []Token {
+	var literal []Token
 
 	if value >= 0 {
-		instruction = Instruction{Body: []Token{SPACE}}
+		literal = []Token{SPACE}
 	} else {
-		instruction = Instruction{Body: []Token{TAB}}
+		literal = []Token{TAB}
 	}
 
-	binaryNumber := strconv.FormatInt(value, 2)
+	binaryNumber := strconv.FormatUint(absoluteValue(value), 2)
 
 	for _, bit := range binaryNumber {
 		if bit == '1' {
-			instruction.Body = append(instruction.Body, TAB)
+			literal = append(literal, TAB)
 
 			continue
 		}
 
-		instruction.Body = append(instruction.Body, SPACE)
+		literal = append(literal, SPACE)
 	}
 
-	instruction.Body = append(instruction.Body, LINE_FEED)
-
-	return instruction
+	return append(literal, LINE_FEED)
 }
 
-func PrintTopStackChar() Instruction {
-	return Instruction{
-		Body: []Token{TAB, LINE_FEED, SPACE, SPACE},
+func Encode(instructions []Instruction) []Token {
+	var tokens []Token
+
+	for _, instruction := range instructions {
+		tokens = append(tokens, instruction.Encode()...)
 	}
+
+	return tokens
 }
 
-func PrintTopStackInteger() Instruction {
-	return Instruction{
-		Body: []Token{TAB, LINE_FEED, SPACE, TAB},
-	}
+// Noop pushes a zero and discards it, leaving the stack as it was
+func Noop() []Instruction {
+	return []Instruction{PushToStack(0), DiscardTopStackItem()}
+}
+
+func PushToStack(value int64) Instruction {
+	return Instruction{Opcode: PUSH, Argument: value}
+}
+
+func DuplicateTopStackItem() Instruction {
+	return Instruction{Opcode: DUPLICATE}
+}
+
+func LiftStackItem(itemOrdinalNumber int) Instruction {
+	return Instruction{Opcode: COPY, Argument: int64(itemOrdinalNumber)}
+}
+
+func SwapTwoTopStackItems() Instruction {
+	return Instruction{Opcode: SWAP}
+}
+
+func DiscardTopStackItem() Instruction {
+	return Instruction{Opcode: DISCARD}
+}
+
+func SlideStackItems(itemCount int) Instruction {
+	return Instruction{Opcode: SLIDE, Argument: int64(itemCount)}
 }
 
 func Add() Instruction {
-	return Instruction{
-		Body: []Token{TAB, SPACE, SPACE, SPACE},
-	}
+	return Instruction{Opcode: ADD}
 }
 
 func Subtract() Instruction {
-	return Instruction{
-		Body: []Token{TAB, SPACE, SPACE, TAB},
-	}
+	return Instruction{Opcode: SUBTRACT}
 }
 
 func Multiply() Instruction {
-	return Instruction{
-		Body: []Token{TAB, SPACE, SPACE, LINE_FEED},
-	}
+	return Instruction{Opcode: MULTIPLY}
 }
 
 func Divide() Instruction {
-	return Instruction{
-		Body: []Token{TAB, SPACE, TAB, SPACE},
-	}
+	return Instruction{Opcode: DIVIDE}
 }
 
 func Mod() Instruction {
-	return Instruction{
-		Body: []Token{TAB, SPACE, TAB, TAB},
-	}
+	return Instruction{Opcode: MOD}
+}
+
+func StoreInHeap() Instruction {
+	return Instruction{Opcode: STORE}
+}
+
+func RetrieveFromHeap() Instruction {
+	return Instruction{Opcode: RETRIEVE}
 }
 
 func Label(labelId int64) Instruction {
-	labelIdLiteral := NumberLiteral(labelId)
+	return Instruction{Opcode: LABEL, Argument: labelId}
+}
 
-	return Instruction{
-		Body: append([]Token{LINE_FEED, SPACE, SPACE}, labelIdLiteral.Body...),
-	}
+func CallSubroutine(labelId int64) Instruction {
+	return Instruction{Opcode: CALL, Argument: labelId}
 }
 
 func JumpToLabel(labelId int64) Instruction {
-	labelIdLiteral := NumberLiteral(labelId)
-
-	return Instruction{
-		Body: append([]Token{LINE_FEED, SPACE, LINE_FEED}, labelIdLiteral.Body...),
-	}
+	return Instruction{Opcode: JUMP, Argument: labelId}
 }
 
 func JumpToLabelIfZero(labelId int64) Instruction {
-	labelIdLiteral := NumberLiteral(labelId)
-
-	return Instruction{
-		Body: append([]Token{LINE_FEED, TAB, SPACE}, labelIdLiteral.Body...),
-	}
+	return Instruction{Opcode: JUMP_IF_ZERO, Argument: labelId}
 }
 
 func JumpToLabelIfNegative(labelId int64) Instruction {
-	labelIdLiteral := NumberLiteral(labelId)
+	return Instruction{Opcode: JUMP_IF_NEGATIVE, Argument: labelId}
+}
 
-	return Instruction{
-		Body: append([]Token{LINE_FEED, TAB, TAB}, labelIdLiteral.Body...),
-	}
+func EndSubroutine() Instruction {
+	return Instruction{Opcode: RETURN}
 }
 
 func EndProgram() Instruction {
-	return Instruction{
-		Body: []Token{LINE_FEED, LINE_FEED, LINE_FEED},
+	return Instruction{Opcode: END}
+}
+
+func PrintTopStackChar() Instruction {
+	return Instruction{Opcode: PRINT_CHAR}
+}
+
+func PrintTopStackInteger() Instruction {
+	return Instruction{Opcode: PRINT_NUMBER}
+}
+
+func ReadCharToHeap() Instruction {
+	return Instruction{Opcode: READ_CHAR}
+}
+
+func ReadIntegerToHeap() Instruction {
+	return Instruction{Opcode: READ_NUMBER}
+}
+
+func absoluteValue(value int64) uint64 {
+	if value < 0 {
+		return uint64(-(value + 1)) + 1
 	}
+
+	return uint64(value)
 }
@@ -0,0 +1,82 @@
+package whitespace
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestInstructions(t *testing.T) {
+	tests := []struct {
+		name                string
+		instruction         Instruction
+		expectedInstruction string
+	}{
+		{name: "push", instruction: PushToStack(5), expectedInstruction: "SS STSTL"},
+		{name: "duplicate", instruction: DuplicateTopStackItem(), expectedInstruction: "SLS"},
+		{name: "copy", instruction: LiftStackItem(2), expectedInstruction: "STS STSL"},
+		{name: "swap", instruction: SwapTwoTopStackItems(), expectedInstruction: "SLT"},
+		{name: "discard", instruction: DiscardTopStackItem(), expectedInstruction: "SLL"},
+		{name: "slide", instruction: SlideStackItems(3), expectedInstruction: "STL STTL"},
+		{name: "add", instruction: Add(), expectedInstruction: "TSSS"},
+		{name: "subtract", instruction: Subtract(), expectedInstruction: "TSST"},
+		{name: "multiply", instruction: Multiply(), expectedInstruction: "TSSL"},
+		{name: "divide", instruction: Divide(), expectedInstruction: "TSTS"},
+		{name: "mod", instruction: Mod(), expectedInstruction: "TSTT"},
+		{name: "store", instruction: StoreInHeap(), expectedInstruction: "TTS"},
+		{name: "retrieve", instruction: RetrieveFromHeap(), expectedInstruction: "TTT"},
+		{name: "label", instruction: Label(6), expectedInstruction: "LSS STTSL"},
+		{name: "call", instruction: CallSubroutine(6), expectedInstruction: "LST STTSL"},
+		{name: "jump", instruction: JumpToLabel(6), expectedInstruction: "LSL STTSL"},
+		{name: "jump if zero", instruction: JumpToLabelIfZero(6), expectedInstruction: "LTS STTSL"},
+		{name: "jump if negative", instruction: JumpToLabelIfNegative(6), expectedInstruction: "LTT STTSL"},
+		{name: "end subroutine", instruction: EndSubroutine(), expectedInstruction: "LTL"},
+		{name: "end program", instruction: EndProgram(), expectedInstruction: "LLL"},
+		{name: "print char", instruction: PrintTopStackChar(), expectedInstruction: "TLSS"},
+		{name: "print integer", instruction: PrintTopStackInteger(), expectedInstruction: "TLST"},
+		{name: "read char", instruction: ReadCharToHeap(), expectedInstruction: "TLTS"},
+		{name: "read integer", instruction: ReadIntegerToHeap(), expectedInstruction: "TLTT"},
+	}
+
+	for _, test := range tests {
+		encodedInstruction := string(test.instruction.Encode())
+
+		if encodedInstruction != fromNotation(test.expectedInstruction) {
+			t.Errorf("%s: instruction incorrect. expected=%q, got=%q", test.name, fromNotation(test.expectedInstruction), encodedInstruction)
+		}
+	}
+}
+
+func TestNoop(t *testing.T) {
+	var noop []Token
+
+	for _, instruction := range Noop() {
+		noop = append(noop, instruction.Encode()...)
+	}
+
+	if string(noop) != fromNotation("SS SSL SLL") {
+		t.Errorf("noop incorrect. expected=%q, got=%q", fromNotation("SS SSL SLL"), string(noop))
+	}
+}
+
+func TestNumberLiteral(t *testing.T) {
+	tests := []struct {
+		value           int64
+		expectedLiteral string
+	}{
+		{value: 0, expectedLiteral: "SSL"},
+		{value: 1, expectedLiteral: "STL"},
+		{value: 10, expectedLiteral: "STSTSL"},
+		{value: -1, expectedLiteral: "TTL"},
+		{value: -10, expectedLiteral: "TTSTSL"},
+		{value: math.MinInt64, expectedLiteral: "TT" + strings.Repeat("S", 63) + "L"},
+	}
+
+	for _, test := range tests {
+		literal := string(NumberLiteral(test.value))
+
+		if literal != fromNotation(test.expectedLiteral) {
+			t.Errorf("number literal %d incorrect. expected=%q, got=%q", test.value, fromNotation(test.expectedLiteral), literal)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package whitespace
+
+// Optimization levels of Optimize
+const (
+	NO_OPTIMIZATION           = 0
+	LOCAL_OPTIMIZATION        = 1
+	CONTROL_FLOW_OPTIMIZATION = 2
+	SIZE_OPTIMIZATION         = 3
+)
+
+// Rewriting stops after this many passes, in case rules keep undoing each other
+const maxOptimizationPasses = 100
+
+// PeepholeRule rewrites a fixed size window of consecutive instructions.
+// Rules only see labels that are part of their window, so code can only be jumped into at the start of a rewritten window.
+type PeepholeRule struct {
+	Name   string
+	Window int
+	// Rewrite returns the instructions replacing the whole window, false when the rule does not apply
+	Rewrite func(context *PeepholeContext, window []Instruction) ([]Instruction, bool)
+}
+
+// PeepholeContext describes the whole program at the start of an optimization pass
+type PeepholeContext struct {
+	instructions    []Instruction
+	labelIndexes    map[int64]int
+	labelReferences map[int64]int
+}
+
+func newPeepholeContext(instructions []Instruction) *PeepholeContext {
+	context := &PeepholeContext{
+		instructions:    instructions,
+		labelIndexes:    make(map[int64]int),
+		labelReferences: make(map[int64]int),
+	}
+
+	for i, instruction := range instructions {
+		if instruction.Opcode == LABEL {
+			context.labelIndexes[instruction.Argument] = i
+		} else if instruction.HasLabelArgument() {
+			context.labelReferences[instruction.Argument]++
+		}
+	}
+
+	return context
+}
+
+// LabelReferences is the number of calls and jumps to the label
+func (c *PeepholeContext) LabelReferences(labelId int64) int {
+	return c.labelReferences[labelId]
+}
+
+// InstructionAfterLabel is the first instruction executed after jumping to the label
+func (c *PeepholeContext) InstructionAfterLabel(labelId int64) (Instruction, bool) {
+	index, ok := c.labelIndexes[labelId]
+	if !ok {
+		return Instruction{}, false
+	}
+
+	for index < len(c.instructions) && c.instructions[index].Opcode == LABEL {
+		index++
+	}
+
+	if index == len(c.instructions) {
+		return Instruction{}, false
+	}
+
+	return c.instructions[index], true
+}
+
+type Optimizer struct {
+	rules []PeepholeRule
+}
+
+func NewOptimizer(rules ...PeepholeRule) *Optimizer {
+	return &Optimizer{rules: rules}
+}
+
+// OptimizationRules are the rules applied at an optimization level, higher levels include the rules of lower ones
+func OptimizationRules(level int) []PeepholeRule {
+	var rules []PeepholeRule
+
+	if level >= LOCAL_OPTIMIZATION {
+		rules = append(rules, LocalRules...)
+	}
+
+	if level >= CONTROL_FLOW_OPTIMIZATION {
+		rules = append(rules, ControlFlowRules...)
+	}
+
+	return rules
+}
+
+// Optimize rewrites a program with the rules of the optimization level, and shrinks its encoding at SIZE_OPTIMIZATION.
+// The output of programs running without errors is preserved.
+func Optimize(instructions []Instruction, level int) []Instruction {
+	instructions = NewOptimizer(OptimizationRules(level)...).Optimize(instructions)
+
+	if level >= SIZE_OPTIMIZATION {
+		instructions = OptimizeSize(instructions)
+	}
+
+	return instructions
+}
+
+// Optimize applies the rules until none of them matches
+func (o *Optimizer) Optimize(instructions []Instruction) []Instruction {
+	if len(o.rules) == 0 {
+		return instructions
+	}
+
+	for range maxOptimizationPasses {
+		optimized, changed := o.optimizationPass(instructions)
+		instructions = optimized
+
+		if !changed {
+			break
+		}
+	}
+
+	return instructions
+}
+
+func (o *Optimizer) optimizationPass(instructions []Instruction) ([]Instruction, bool) {
+	context := newPeepholeContext(instructions)
+	optimized := make([]Instruction, 0, len(instructions))
+	changed := false
+
+	for i := 0; i < len(instructions); {
+		rewritten := false
+
+		for _, rule := range o.rules {
+			if i+rule.Window > len(instructions) {
+				continue
+			}
+
+			replacement, ok := rule.Rewrite(context, instructions[i:i+rule.Window])
+			if !ok {
+				continue
+			}
+
+			optimized = append(optimized, replacement...)
+			i += rule.Window
+			rewritten = true
+
+			break
+		}
+
+		if rewritten {
+			changed = true
+
+			continue
+		}
+
+		optimized = append(optimized, instructions[i])
+		i++
+	}
+
+	return optimized, changed
+}
+
+var LocalRules = []PeepholeRule{
+	{
+		Name:   "fold constant arithmetic",
+		Window: 3,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != PUSH || window[1].Opcode != PUSH || !isArithmetic(window[2].Opcode) {
+				return nil, false
+			}
+
+			// Division by zero is left for the program to fail on
+			result, err := arithmeticResult(window[2].Opcode, window[0].Argument, window[1].Argument)
+			if err != nil {
+				return nil, false
+			}
+
+			return []Instruction{PushToStack(result)}, true
+		},
+	},
+	{
+		Name:   "push swapped constants",
+		Window: 3,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != PUSH || window[1].Opcode != PUSH || window[2].Opcode != SWAP {
+				return nil, false
+			}
+
+			return []Instruction{window[1], window[0]}, true
+		},
+	},
+	{
+		Name:   "reuse stored value",
+		Window: 5,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != PUSH || window[1].Opcode != SWAP || window[2].Opcode != STORE ||
+				window[3].Opcode != PUSH || window[4].Opcode != RETRIEVE || window[0].Argument != window[3].Argument {
+				return nil, false
+			}
+
+			return []Instruction{DuplicateTopStackItem(), window[0], window[1], window[2]}, true
+		},
+	},
+	{
+		Name:   "drop pushed value",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if (window[0].Opcode != PUSH && window[0].Opcode != DUPLICATE) || window[1].Opcode != DISCARD {
+				return nil, false
+			}
+
+			return nil, true
+		},
+	},
+	{
+		Name:   "double swap",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != SWAP || window[1].Opcode != SWAP {
+				return nil, false
+			}
+
+			return nil, true
+		},
+	},
+	{
+		Name:   "identity arithmetic",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != PUSH {
+				return nil, false
+			}
+
+			switch {
+			case window[0].Argument == 0 && (window[1].Opcode == ADD || window[1].Opcode == SUBTRACT):
+				return nil, true
+			case window[0].Argument == 1 && (window[1].Opcode == MULTIPLY || window[1].Opcode == DIVIDE):
+				return nil, true
+			default:
+				return nil, false
+			}
+		},
+	},
+	{
+		Name:   "constant condition",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != PUSH {
+				return nil, false
+			}
+
+			switch window[1].Opcode {
+			case JUMP_IF_ZERO:
+				if window[0].Argument == 0 {
+					return []Instruction{JumpToLabel(window[1].Argument)}, true
+				}
+
+				return nil, true
+			case JUMP_IF_NEGATIVE:
+				if window[0].Argument < 0 {
+					return []Instruction{JumpToLabel(window[1].Argument)}, true
+				}
+
+				return nil, true
+			default:
+				return nil, false
+			}
+		},
+	},
+}
+
+var ControlFlowRules = []PeepholeRule{
+	{
+		Name:   "jump to next instruction",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != JUMP || window[1].Opcode != LABEL || window[0].Argument != window[1].Argument {
+				return nil, false
+			}
+
+			return []Instruction{window[1]}, true
+		},
+	},
+	{
+		Name:   "unreachable code",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != JUMP && window[0].Opcode != RETURN && window[0].Opcode != END {
+				return nil, false
+			}
+
+			if window[1].Opcode == LABEL {
+				return nil, false
+			}
+
+			return []Instruction{window[0]}, true
+		},
+	},
+	{
+		Name:   "unused label",
+		Window: 1,
+		Rewrite: func(context *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != LABEL || context.LabelReferences(window[0].Argument) > 0 {
+				return nil, false
+			}
+
+			return nil, true
+		},
+	},
+	{
+		Name:   "jump threading",
+		Window: 1,
+		Rewrite: func(context *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			jump := window[0]
+			if jump.Opcode != JUMP && jump.Opcode != JUMP_IF_ZERO && jump.Opcode != JUMP_IF_NEGATIVE {
+				return nil, false
+			}
+
+			target, ok := context.InstructionAfterLabel(jump.Argument)
+			if !ok {
+				return nil, false
+			}
+
+			switch {
+			case target.Opcode == JUMP && target.Argument != jump.Argument:
+				return []Instruction{{Opcode: jump.Opcode, Argument: target.Argument}}, true
+			case jump.Opcode == JUMP && (target.Opcode == RETURN || target.Opcode == END):
+				return []Instruction{target}, true
+			default:
+				return nil, false
+			}
+		},
+	},
+}
+
+func isArithmetic(opcode Opcode) bool {
+	return opcode == ADD || opcode == SUBTRACT || opcode == MULTIPLY || opcode == DIVIDE || opcode == MOD
+}
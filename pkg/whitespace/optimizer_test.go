@@ -0,0 +1,282 @@
+package whitespace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOptimizerRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		source   string
+		expected string
+	}{
+		{
+			name:     "no optimization",
+			level:    NO_OPTIMIZATION,
+			source:   "push 2\npush 3\nadd\noutn\nend",
+			expected: "push 2\npush 3\nadd\noutn\nend",
+		},
+		{
+			name:     "fold constant arithmetic",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "push 2\npush 3\nadd\npush 4\nmul\npush -7\npush 2\ndiv\nsub\noutn\nend",
+			expected: "push 24\noutn\nend",
+		},
+		{
+			name:     "division by zero is kept",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "push 1\npush 0\ndiv\noutn\nend",
+			expected: "push 1\npush 0\ndiv\noutn\nend",
+		},
+		{
+			name:     "push swapped constants",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "push 7\npush 1\nswap\nstore\nend",
+			expected: "push 1\npush 7\nstore\nend",
+		},
+		{
+			name:     "reuse stored value",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "inn\npush 1\nswap\nstore\npush 1\nretrieve\noutn\nend",
+			expected: "inn\ndup\npush 1\nswap\nstore\noutn\nend",
+		},
+		{
+			name:     "redundant stack operations",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "push 5\ndup\ndrop\nswap\nswap\npush 9\ndrop\npush 0\nadd\npush 1\nmul\noutn\nend",
+			expected: "push 5\noutn\nend",
+		},
+		{
+			name:     "constant conditions",
+			level:    LOCAL_OPTIMIZATION,
+			source:   "push 0\njz L1\npush 3\njz L1\npush 1\njn L1\npush -1\njn L1\nL1:\nend",
+			expected: "jmp L1\njmp L1\nL1:\nend",
+		},
+		{
+			name:     "jump to next instruction",
+			level:    CONTROL_FLOW_OPTIMIZATION,
+			source:   "inn\njz L1\njmp L1\nL1:\nend",
+			expected: "inn\njz L1\nL1:\nend",
+		},
+		{
+			name:     "unreachable code and unused labels",
+			level:    CONTROL_FLOW_OPTIMIZATION,
+			source:   "call L1\nend\npush 1\noutn\nL2:\npush 2\nL1:\nret\npush 3\nret",
+			expected: "call L1\nend\nL1:\nret",
+		},
+		{
+			name:     "jump threading",
+			level:    CONTROL_FLOW_OPTIMIZATION,
+			source:   "inn\njz L1\npush 1\noutn\njmp L2\nL1:\njmp L2\nL2:\njmp L3\nL3:\nend",
+			expected: "inn\njz L2\npush 1\noutn\nend\nL2:\nend",
+		},
+		{
+			name:     "local rules at control flow level",
+			level:    CONTROL_FLOW_OPTIMIZATION,
+			source:   "push 1\njz L1\npush 2\noutn\nL1:\nend",
+			expected: "push 2\noutn\nend",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instructions, err := Assemble(strings.NewReader(test.source))
+			if err != nil {
+				t.Fatalf("cannot assemble source, error: %v", err)
+			}
+
+			expectedInstructions, err := Assemble(strings.NewReader(test.expected))
+			if err != nil {
+				t.Fatalf("cannot assemble expected program, error: %v", err)
+			}
+
+			optimizedInstructions := Optimize(instructions, test.level)
+
+			if len(optimizedInstructions) != len(expectedInstructions) {
+				t.Fatalf("instruction count incorrect. expected=%v, got=%v", expectedInstructions, optimizedInstructions)
+			}
+
+			for i, expectedInstruction := range expectedInstructions {
+				if optimizedInstructions[i] != expectedInstruction {
+					t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i, expectedInstruction, optimizedInstructions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOptimizerCustomRules(t *testing.T) {
+	negateTwice := PeepholeRule{
+		Name:   "double negation",
+		Window: 4,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			negation := []Instruction{PushToStack(-1), Multiply()}
+
+			if window[0] != negation[0] || window[1] != negation[1] || window[2] != negation[0] || window[3] != negation[1] {
+				return nil, false
+			}
+
+			return nil, true
+		},
+	}
+
+	instructions := []Instruction{PushToStack(4), PushToStack(-1), Multiply(), PushToStack(-1), Multiply(), PrintTopStackInteger(), EndProgram()}
+
+	optimizedInstructions := NewOptimizer(negateTwice).Optimize(instructions)
+
+	expectedInstructions := []Instruction{PushToStack(4), PrintTopStackInteger(), EndProgram()}
+
+	if len(optimizedInstructions) != len(expectedInstructions) {
+		t.Fatalf("instruction count incorrect. expected=%v, got=%v", expectedInstructions, optimizedInstructions)
+	}
+
+	for i, expectedInstruction := range expectedInstructions {
+		if optimizedInstructions[i] != expectedInstruction {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i, expectedInstruction, optimizedInstructions[i])
+		}
+	}
+}
+
+func TestOptimizerPreservesOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		input  string
+	}{
+		{
+			name: "countdown",
+			source: `
+    push 5
+    push 1
+    swap
+    store
+loop:
+    push 1
+    retrieve
+    dup
+    outn
+    push 1
+    sub
+    push 1
+    swap
+    store
+    push 1
+    retrieve
+    jz done
+    jmp loop
+done:
+    jmp finish
+finish:
+    push '\n'
+    outc
+    end
+`,
+		},
+		{
+			name: "arithmetic",
+			source: `
+    push -7
+    push 2
+    div
+    outn
+    push -7
+    push 2
+    mod
+    outn
+    push 3
+    push 0
+    add
+    push 1
+    mul
+    push 1
+    div
+    outn
+    push 6
+    push 7
+    swap
+    sub
+    outn
+    end
+`,
+		},
+		{
+			name: "subroutines and input",
+			source: `
+    push 10
+    inn
+    push 10
+    retrieve
+    call square
+    outn
+    push 1
+    jn skip
+    push 0
+    jz print
+    push 9
+    outn
+skip:
+    push 8
+    outn
+print:
+    call newline
+    end
+square:
+    dup
+    mul
+    ret
+    push 1
+    outn
+newline:
+    jmp emit
+emit:
+    push '\n'
+    outc
+    ret
+`,
+			input: "-12\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instructions, err := Assemble(strings.NewReader(test.source))
+			if err != nil {
+				t.Fatalf("cannot assemble source, error: %v", err)
+			}
+
+			expectedOutput := runInstructions(t, instructions, test.input)
+
+			for _, level := range []int{LOCAL_OPTIMIZATION, CONTROL_FLOW_OPTIMIZATION} {
+				optimizedInstructions := Optimize(instructions, level)
+
+				if len(optimizedInstructions) >= len(instructions) {
+					t.Errorf("level %d did not shorten the program, %d instruction(s)", level, len(optimizedInstructions))
+				}
+
+				if output := runInstructions(t, optimizedInstructions, test.input); output != expectedOutput {
+					t.Errorf("output at level %d incorrect. expected=%q, got=%q", level, expectedOutput, output)
+				}
+			}
+		})
+	}
+}
+
+func runInstructions(t *testing.T, instructions []Instruction, input string) string {
+	t.Helper()
+
+	var output bytes.Buffer
+
+	vm, err := NewVirtualMachine(instructions, strings.NewReader(input), &output)
+	if err != nil {
+		t.Fatalf("cannot load program, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("cannot run program, error: %v", err)
+	}
+
+	return output.String()
+}
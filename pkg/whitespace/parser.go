@@ -0,0 +1,217 @@
+package whitespace
+
+import (
+	"fmt"
+	"io"
+)
+
+type ParsedInstruction struct {
+	Instruction Instruction
+	Offset      int
+}
+
+type parser struct {
+	tokens       []Token
+	tokenOffsets []int
+	position     int
+
+	labels []string
+}
+
+func Parse(source io.Reader) ([]Instruction, error) {
+	parsedInstructions, err := ParseWithOffsets(source)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := make([]Instruction, len(parsedInstructions))
+
+	for i, parsedInstruction := range parsedInstructions {
+		instructions[i] = parsedInstruction.Instruction
+	}
+
+	return instructions, nil
+}
+
+func ParseWithOffsets(source io.Reader) ([]ParsedInstruction, error) {
+	content, err := io.ReadAll(source)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read whitespace source, error: %v", err)
+	}
+
+	p := &parser{}
+
+	for offset, char := range content {
+		if char == SPACE || char == TAB || char == LINE_FEED {
+			p.tokens = append(p.tokens, Token(char))
+			p.tokenOffsets = append(p.tokenOffsets, offset)
+		}
+	}
+
+	var parsedInstructions []ParsedInstruction
+
+	for p.position < len(p.tokens) {
+		offset := p.tokenOffsets[p.position]
+
+		instruction, err := p.parseInstruction()
+		if err != nil {
+			return nil, err
+		}
+
+		parsedInstructions = append(parsedInstructions, ParsedInstruction{Instruction: instruction, Offset: offset})
+	}
+
+	p.resolveLabels(parsedInstructions)
+
+	return parsedInstructions, nil
+}
+
+func (p *parser) parseInstruction() (Instruction, error) {
+	startPosition := p.position
+
+	for _, encoding := range opcodeEncodings {
+		if !p.hasPrefix(encoding.tokens) {
+			continue
+		}
+
+		p.position += len(encoding.tokens)
+
+		instruction := Instruction{Opcode: encoding.opcode}
+
+		switch encoding.argument {
+		case numberArgument:
+			number, err := p.parseNumber()
+			if err != nil {
+				return Instruction{}, err
+			}
+
+			instruction.Argument = number
+		case labelArgument:
+			label, err := p.readUntilLineFeed()
+			if err != nil {
+				return Instruction{}, err
+			}
+
+			p.labels = append(p.labels, string(label))
+		}
+
+		return instruction, nil
+	}
+
+	return Instruction{}, fmt.Errorf("[offset %d] unknown instruction", p.tokenOffsets[startPosition])
+}
+
+func (p *parser) hasPrefix(prefix []Token) bool {
+	if len(p.tokens)-p.position < len(prefix) {
+		return false
+	}
+
+	for i, token := range prefix {
+		if p.tokens[p.position+i] != token {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *parser) parseNumber() (int64, error) {
+	startPosition := p.position
+
+	bits, err := p.readUntilLineFeed()
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := parseNumberBits(bits)
+	if !ok {
+		return 0, fmt.Errorf("[offset %d] number literal overflow", p.tokenOffsets[startPosition])
+	}
+
+	return value, nil
+}
+
+func (p *parser) readUntilLineFeed() ([]Token, error) {
+	startPosition := p.position
+
+	for p.position < len(p.tokens) {
+		token := p.tokens[p.position]
+		p.position++
+
+		if token == LINE_FEED {
+			return p.tokens[startPosition : p.position-1], nil
+		}
+	}
+
+	return nil, fmt.Errorf("[offset %d] unterminated argument", p.tokenOffsets[startPosition-1])
+}
+
+// Labels are arbitrary bit strings, while instructions identify them by number.
+// Labels in the form produced by NumberLiteral keep their value, any other label gets an unused id.
+func (p *parser) resolveLabels(parsedInstructions []ParsedInstruction) {
+	labelIds := make(map[string]int64)
+
+	var nextLabelId int64
+
+	for _, label := range p.labels {
+		labelId, ok := parseNumberBits([]Token(label))
+		if ok && string(NumberLiteral(labelId)) == label+string(LINE_FEED) {
+			labelIds[label] = labelId
+			nextLabelId = max(nextLabelId, labelId+1)
+		}
+	}
+
+	labelIndex := 0
+
+	for i, parsedInstruction := range parsedInstructions {
+		if !parsedInstruction.Instruction.HasLabelArgument() {
+			continue
+		}
+
+		label := p.labels[labelIndex]
+		labelIndex++
+
+		labelId, ok := labelIds[label]
+		if !ok {
+			labelId = nextLabelId
+			labelIds[label] = labelId
+			nextLabelId++
+		}
+
+		parsedInstructions[i].Instruction.Argument = labelId
+	}
+}
+
+func parseNumberBits(bits []Token) (int64, bool) {
+	if len(bits) == 0 {
+		return 0, true
+	}
+
+	var magnitude uint64
+
+	for _, bit := range bits[1:] {
+		if magnitude > (1<<63)>>1 {
+			return 0, false
+		}
+
+		magnitude <<= 1
+
+		if bit == TAB {
+			magnitude |= 1
+		}
+	}
+
+	if bits[0] == TAB {
+		if magnitude > 1<<63 {
+			return 0, false
+		}
+
+		return -int64(magnitude-1) - 1, true
+	}
+
+	if magnitude > 1<<63-1 {
+		return 0, false
+	}
+
+	return int64(magnitude), true
+}
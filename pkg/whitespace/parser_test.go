@@ -0,0 +1,108 @@
+package whitespace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	source := "let\u2007x=1;" + fromNotation("SS STSTL") + "\r//" + fromNotation("TTS LSS STTL LTS STTL") + "x++;" + fromNotation("LLL")
+
+	expectedInstructions := []ParsedInstruction{
+		{Instruction: PushToStack(5), Offset: 10},
+		{Instruction: StoreInHeap(), Offset: 20},
+		{Instruction: Label(3), Offset: 23},
+		{Instruction: JumpToLabelIfZero(3), Offset: 30},
+		{Instruction: EndProgram(), Offset: 41},
+	}
+
+	parsedInstructions, err := ParseWithOffsets(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	if len(parsedInstructions) != len(expectedInstructions) {
+		t.Fatalf("instruction count incorrect. expected=%d, got=%d", len(expectedInstructions), len(parsedInstructions))
+	}
+
+	for i, parsedInstruction := range parsedInstructions {
+		if parsedInstruction != expectedInstructions[i] {
+			t.Errorf("instruction (#%d) incorrect. expected=%+v, got=%+v", i+1, expectedInstructions[i], parsedInstruction)
+		}
+	}
+}
+
+func TestParserRoundTrip(t *testing.T) {
+	instructions := []Instruction{
+		PushToStack(-42),
+		DuplicateTopStackItem(),
+		LiftStackItem(3),
+		SlideStackItems(2),
+		Label(0),
+		CallSubroutine(7),
+		JumpToLabelIfNegative(0),
+		Label(7),
+		EndSubroutine(),
+		ReadIntegerToHeap(),
+		EndProgram(),
+	}
+
+	parsedInstructions, err := Parse(strings.NewReader(string(Encode(instructions))))
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	for i, parsedInstruction := range parsedInstructions {
+		if parsedInstruction != instructions[i] {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i+1, instructions[i], parsedInstruction)
+		}
+	}
+}
+
+func TestParserNonCanonicalLabels(t *testing.T) {
+	source := fromNotation("LSS STL LSS SSTL LSS L LSL SSTL LSL L LSL STL LLL")
+
+	parsedInstructions, err := Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("cannot parse source, error: %v", err)
+	}
+
+	labelIds := []int64{
+		parsedInstructions[0].Argument,
+		parsedInstructions[1].Argument,
+		parsedInstructions[2].Argument,
+	}
+
+	if labelIds[0] != 1 || labelIds[1] == labelIds[0] || labelIds[2] == labelIds[0] || labelIds[2] == labelIds[1] {
+		t.Fatalf("labels not distinct. got=%v", labelIds)
+	}
+
+	for i, jumpInstruction := range parsedInstructions[3:6] {
+		if jumpInstruction.Argument != labelIds[(i+1)%3] {
+			t.Errorf("jump (#%d) target incorrect. expected=L%d, got=L%d", i+1, labelIds[(i+1)%3], jumpInstruction.Argument)
+		}
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	source := fromNotation("SS STSTL TTS LSS STTL LTS STTL TLSS LLL")
+
+	expectedListing := `     0      push 5
+     7      store
+    10  label L3
+    17      jz L3
+    24      outc
+    28      end
+`
+
+	var listing bytes.Buffer
+
+	if err := Disassemble(strings.NewReader(source), &listing); err != nil {
+		t.Fatalf("cannot disassemble source, error: %v", err)
+	}
+
+	if listing.String() != expectedListing {
+		t.Errorf("listing incorrect. expected=\n%s\ngot=\n%s", expectedListing, listing.String())
+	}
+}
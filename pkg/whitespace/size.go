@@ -0,0 +1,330 @@
+package whitespace
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Longest printed fragment considered for reuse, bounding the number of candidates
+const maxPrintedFragmentLength = 64
+
+// OptimizeSize rewrites a program to encode into fewer tokens: printed fragments repeated in the program become subroutines,
+// long printed strings are pushed at once and printed by a loop, and the most used labels get the shortest bit strings.
+func OptimizeSize(instructions []Instruction) []Instruction {
+	instructions = extractPrintedFragments(instructions)
+	instructions = loopPrintedStrings(instructions)
+	instructions = NewOptimizer(SizeRules...).Optimize(instructions)
+
+	return compactLabels(instructions)
+}
+
+var SizeRules = []PeepholeRule{
+	{
+		Name:   "tail call",
+		Window: 2,
+		Rewrite: func(_ *PeepholeContext, window []Instruction) ([]Instruction, bool) {
+			if window[0].Opcode != CALL || window[1].Opcode != RETURN {
+				return nil, false
+			}
+
+			return []Instruction{JumpToLabel(window[0].Argument)}, true
+		},
+	},
+}
+
+func tokenCount(instructions ...Instruction) int {
+	count := 0
+
+	for _, instruction := range instructions {
+		count += len(instruction.Encode())
+	}
+
+	return count
+}
+
+// printedRun is a maximal sequence of chars pushed and printed one by one
+type printedRun struct {
+	start int
+	chars []rune
+}
+
+func (r printedRun) end() int {
+	return r.start + 2*len(r.chars)
+}
+
+func printedRuns(instructions []Instruction) []printedRun {
+	var runs []printedRun
+
+	for i := 0; i < len(instructions); {
+		run := printedRun{start: i}
+
+		for i+1 < len(instructions) && instructions[i].Opcode == PUSH && instructions[i+1].Opcode == PRINT_CHAR &&
+			instructions[i].Argument <= utf8.MaxRune && utf8.ValidRune(rune(instructions[i].Argument)) {
+			run.chars = append(run.chars, rune(instructions[i].Argument))
+			i += 2
+		}
+
+		if len(run.chars) == 0 {
+			i++
+
+			continue
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs
+}
+
+func printCharsInstructions(chars []rune) []Instruction {
+	var instructions []Instruction
+
+	for _, char := range chars {
+		instructions = append(instructions, PushToStack(int64(char)), PrintTopStackChar())
+	}
+
+	return instructions
+}
+
+func nextLabelId(instructions []Instruction) int64 {
+	var labelId int64
+
+	for _, instruction := range instructions {
+		if instruction.HasLabelArgument() {
+			labelId = max(labelId, instruction.Argument+1)
+		}
+	}
+
+	return labelId
+}
+
+// appendSubroutine places a subroutine after the program, which is ended first if it could run into the subroutine
+func appendSubroutine(instructions []Instruction, subroutine []Instruction) []Instruction {
+	last := instructions[len(instructions)-1].Opcode
+	if last != END && last != RETURN && last != JUMP {
+		instructions = append(instructions, EndProgram())
+	}
+
+	return append(instructions, subroutine...)
+}
+
+// extractPrintedFragments moves the fragment saving the most tokens into a subroutine, until no fragment saves any
+func extractPrintedFragments(instructions []Instruction) []Instruction {
+	for {
+		labelId := nextLabelId(instructions)
+		runs := printedRuns(instructions)
+
+		fragment, ok := mostSavingFragment(runs, labelId)
+		if !ok {
+			return instructions
+		}
+
+		var replaced []Instruction
+
+		previousEnd := 0
+		for _, run := range runs {
+			replaced = append(replaced, instructions[previousEnd:run.start]...)
+
+			for i, part := range strings.Split(string(run.chars), fragment) {
+				if i > 0 {
+					replaced = append(replaced, CallSubroutine(labelId))
+				}
+
+				replaced = append(replaced, printCharsInstructions([]rune(part))...)
+			}
+
+			previousEnd = run.end()
+		}
+
+		replaced = append(replaced, instructions[previousEnd:]...)
+
+		subroutine := append([]Instruction{Label(labelId)}, printCharsInstructions([]rune(fragment))...)
+
+		instructions = appendSubroutine(replaced, append(subroutine, EndSubroutine()))
+	}
+}
+
+type fragmentCandidate struct {
+	positions  int
+	runIndexes []int
+}
+
+func mostSavingFragment(runs []printedRun, labelId int64) (string, bool) {
+	candidates := make(map[string]*fragmentCandidate)
+
+	for runIndex, run := range runs {
+		for start := range run.chars {
+			for length := 2; length <= maxPrintedFragmentLength && start+length <= len(run.chars); length++ {
+				fragment := string(run.chars[start : start+length])
+
+				candidate, ok := candidates[fragment]
+				if !ok {
+					candidate = &fragmentCandidate{}
+					candidates[fragment] = candidate
+				}
+
+				candidate.positions++
+
+				if len(candidate.runIndexes) == 0 || candidate.runIndexes[len(candidate.runIndexes)-1] != runIndex {
+					candidate.runIndexes = append(candidate.runIndexes, runIndex)
+				}
+			}
+		}
+	}
+
+	callCost := tokenCount(CallSubroutine(labelId))
+	definitionCost := tokenCount(Label(labelId), EndSubroutine())
+
+	bestFragment, bestSaving := "", 0
+
+	for fragment, candidate := range candidates {
+		// Fragments printed only once are not worth a subroutine
+		if candidate.positions < 2 {
+			continue
+		}
+
+		occurrences := 0
+		for _, runIndex := range candidate.runIndexes {
+			occurrences += strings.Count(string(runs[runIndex].chars), fragment)
+		}
+
+		if occurrences < 2 {
+			continue
+		}
+
+		printCost := tokenCount(printCharsInstructions([]rune(fragment))...)
+		saving := occurrences*(printCost-callCost) - printCost - definitionCost
+
+		if saving > bestSaving || (saving == bestSaving && saving > 0 && fragment < bestFragment) {
+			bestFragment, bestSaving = fragment, saving
+		}
+	}
+
+	return bestFragment, bestSaving > 0
+}
+
+// loopPrintedStrings pushes the chars of long printed strings on a zero terminator in reverse, and prints them with a shared loop
+func loopPrintedStrings(instructions []Instruction) []Instruction {
+	labelId := nextLabelId(instructions)
+	endLabelId := labelId + 1
+
+	// Stack: 0, last char, ..., first char -> (empty)
+	subroutine := []Instruction{
+		Label(labelId),
+		DuplicateTopStackItem(),
+		JumpToLabelIfZero(endLabelId),
+		PrintTopStackChar(),
+		JumpToLabel(labelId),
+		Label(endLabelId),
+		DiscardTopStackItem(),
+		EndSubroutine(),
+	}
+
+	loopCost := tokenCount(PushToStack(0), CallSubroutine(labelId))
+	printCost := tokenCount(PrintTopStackChar())
+
+	looped := make(map[int]printedRun)
+	saving := -tokenCount(subroutine...)
+
+	for _, run := range printedRuns(instructions) {
+		if slices.Contains(run.chars, 0) || len(run.chars)*printCost <= loopCost {
+			continue
+		}
+
+		looped[run.start] = run
+		saving += len(run.chars)*printCost - loopCost
+	}
+
+	if saving <= 0 {
+		return instructions
+	}
+
+	var replaced []Instruction
+
+	for i := 0; i < len(instructions); {
+		run, ok := looped[i]
+		if !ok {
+			replaced = append(replaced, instructions[i])
+			i++
+
+			continue
+		}
+
+		replaced = append(replaced, PushToStack(0))
+
+		for j := len(run.chars) - 1; j >= 0; j-- {
+			replaced = append(replaced, PushToStack(int64(run.chars[j])))
+		}
+
+		replaced = append(replaced, CallSubroutine(labelId))
+		i = run.end()
+	}
+
+	return appendSubroutine(replaced, subroutine)
+}
+
+// compactLabels numbers labels in the order of their use count and encodes them with compactLabelLiteral,
+// the shortest bit strings going to the most used labels
+func compactLabels(instructions []Instruction) []Instruction {
+	uses := make(map[int64]int)
+
+	var labelIds []int64
+
+	for _, instruction := range instructions {
+		if !instruction.HasLabelArgument() {
+			continue
+		}
+
+		if uses[instruction.Argument] == 0 {
+			labelIds = append(labelIds, instruction.Argument)
+		}
+
+		uses[instruction.Argument]++
+	}
+
+	slices.SortStableFunc(labelIds, func(a, b int64) int {
+		return cmp.Compare(uses[b], uses[a])
+	})
+
+	compactIds := make(map[int64]int64, len(labelIds))
+	for i, labelId := range labelIds {
+		compactIds[labelId] = int64(i)
+	}
+
+	compacted := make([]Instruction, len(instructions))
+
+	for i, instruction := range instructions {
+		if instruction.HasLabelArgument() {
+			instruction.Argument = compactIds[instruction.Argument]
+			instruction.CompactLabel = true
+		}
+
+		compacted[i] = instruction
+	}
+
+	return compacted
+}
+
+// compactLabelLiteral encodes a label id as the bit string at that position in the order of length, then of value:
+// empty, S, T, SS, ST, TS, TT and so on. These are the bits of labelId+1 without the leading one.
+// Such labels are not in the form produced by NumberLiteral, so the parser gives them unused ids.
+func compactLabelLiteral(labelId int64) []Token {
+	var literal []Token
+
+	binaryNumber := strconv.FormatUint(uint64(labelId)+1, 2)
+
+	for _, bit := range binaryNumber[1:] {
+		if bit == '1' {
+			literal = append(literal, TAB)
+
+			continue
+		}
+
+		literal = append(literal, SPACE)
+	}
+
+	return append(literal, LINE_FEED)
+}
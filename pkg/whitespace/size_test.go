@@ -0,0 +1,138 @@
+package whitespace
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOptimizeSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "repeated fragments",
+			source: printCharsAssembly("Hello, World!\n") + printCharsAssembly("Hello, there\n") + printCharsAssembly("Hello, World!\n") + "end",
+		},
+		{
+			name:   "long string",
+			source: printCharsAssembly("The quick brown fox jumps over the lazy dog\n") + "end",
+		},
+		{
+			name:   "fragments in subroutines",
+			source: "call greet\n" + printCharsAssembly("bye, bye\n") + "end\ngreet:\n" + printCharsAssembly("hi, bye\n") + "ret",
+		},
+		{
+			name:   "jumps around printed strings",
+			source: "push 0\njz skip\n" + printCharsAssembly("never printed, never printed") + "skip:\n" + printCharsAssembly("printed, printed\n") + "push 0\njz done\ndone:\nend",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instructions, err := Assemble(strings.NewReader(test.source))
+			if err != nil {
+				t.Fatalf("cannot assemble source, error: %v", err)
+			}
+
+			optimizedInstructions := OptimizeSize(instructions)
+
+			if err = Validate(optimizedInstructions); err != nil {
+				t.Fatalf("invalid optimized program, error: %v", err)
+			}
+
+			if tokens, optimizedTokens := len(Encode(instructions)), len(Encode(optimizedInstructions)); optimizedTokens >= tokens {
+				t.Errorf("program was not shortened, %d token(s) before, %d after", tokens, optimizedTokens)
+			}
+
+			if expected, output := runInstructions(t, instructions, ""), runInstructions(t, optimizedInstructions, ""); output != expected {
+				t.Errorf("output incorrect. expected=%q, got=%q", expected, output)
+			}
+		})
+	}
+}
+
+func TestOptimizeSizeKeepsShortStrings(t *testing.T) {
+	source := printCharsAssembly("ab") + "push 1\noutn\n" + printCharsAssembly("cd") + "end"
+
+	instructions, err := Assemble(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("cannot assemble source, error: %v", err)
+	}
+
+	optimizedInstructions := OptimizeSize(instructions)
+
+	if len(optimizedInstructions) != len(instructions) {
+		t.Fatalf("instruction count incorrect. expected=%v, got=%v", instructions, optimizedInstructions)
+	}
+
+	for i, instruction := range instructions {
+		if optimizedInstructions[i] != instruction {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i, instruction, optimizedInstructions[i])
+		}
+	}
+}
+
+func TestCompactLabels(t *testing.T) {
+	source := "jmp L40\nL7:\nret\nL40:\ncall L7\ncall L7\ncall L7\njz L40\nL12:\nend"
+	expected := "jmp L1\nL0:\nret\nL1:\ncall L0\ncall L0\ncall L0\njz L1\nL2:\nend"
+
+	instructions, err := Assemble(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("cannot assemble source, error: %v", err)
+	}
+
+	expectedInstructions, err := Assemble(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("cannot assemble expected program, error: %v", err)
+	}
+
+	compactedInstructions := compactLabels(instructions)
+
+	for i, expectedInstruction := range expectedInstructions {
+		expectedInstruction.CompactLabel = expectedInstruction.HasLabelArgument()
+
+		if compactedInstructions[i] != expectedInstruction {
+			t.Errorf("instruction (#%d) incorrect. expected=%q, got=%q", i, expectedInstruction, compactedInstructions[i])
+		}
+	}
+
+	// L0, L1 and L2 are encoded as empty, S and T
+	if tokens := len(Encode(compactedInstructions)); tokens != 42 {
+		t.Errorf("token count incorrect. expected=%d, got=%d", 42, tokens)
+	}
+}
+
+func TestCompactLabelLiteral(t *testing.T) {
+	tests := []struct {
+		labelId         int64
+		expectedLiteral string
+	}{
+		{labelId: 0, expectedLiteral: "L"},
+		{labelId: 1, expectedLiteral: "SL"},
+		{labelId: 2, expectedLiteral: "TL"},
+		{labelId: 3, expectedLiteral: "SSL"},
+		{labelId: 6, expectedLiteral: "TTL"},
+		{labelId: math.MaxInt64, expectedLiteral: strings.Repeat("S", 63) + "L"},
+	}
+
+	for _, test := range tests {
+		literal := string(compactLabelLiteral(test.labelId))
+
+		if literal != fromNotation(test.expectedLiteral) {
+			t.Errorf("label literal %d incorrect. expected=%q, got=%q", test.labelId, fromNotation(test.expectedLiteral), literal)
+		}
+	}
+}
+
+func printCharsAssembly(value string) string {
+	var source strings.Builder
+
+	for _, char := range value {
+		source.WriteString("push " + strconv.Itoa(int(char)) + "\noutc\n")
+	}
+
+	return source.String()
+}
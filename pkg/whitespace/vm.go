@@ -0,0 +1,385 @@
+package whitespace
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type VirtualMachine struct {
+	instructions []Instruction
+	labels       map[int64]int
+
+	stack     []int64
+	heap      map[int64]int64
+	callStack []int
+
+	input  *bufio.Reader
+	output *bufio.Writer
+}
+
+func NewVirtualMachine(instructions []Instruction, input io.Reader, output io.Writer) (*VirtualMachine, error) {
+	vm := &VirtualMachine{
+		instructions: instructions,
+		labels:       make(map[int64]int),
+		heap:         make(map[int64]int64),
+		input:        bufio.NewReader(input),
+		output:       bufio.NewWriter(output),
+	}
+
+	if err := Validate(instructions); err != nil {
+		return nil, err
+	}
+
+	for i, instruction := range instructions {
+		if instruction.Opcode == LABEL {
+			vm.labels[instruction.Argument] = i
+		}
+	}
+
+	return vm, nil
+}
+
+func Validate(instructions []Instruction) error {
+	if len(instructions) == 0 {
+		return errors.New("empty program")
+	}
+
+	labels := make(map[int64]bool)
+
+	for i, instruction := range instructions {
+		if _, ok := lookupOpcodeEncoding(instruction.Opcode); !ok {
+			return fmt.Errorf("[#%d] unknown opcode %q", i+1, instruction.Opcode)
+		}
+
+		// Compact label ids are positions in a list of bit strings
+		if instruction.CompactLabel && instruction.Argument < 0 {
+			return fmt.Errorf("[#%d] negative compact label L%d", i+1, instruction.Argument)
+		}
+
+		if instruction.Opcode != LABEL {
+			continue
+		}
+
+		if labels[instruction.Argument] {
+			return fmt.Errorf("[#%d] duplicate label L%d", i+1, instruction.Argument)
+		}
+
+		labels[instruction.Argument] = true
+	}
+
+	for i, instruction := range instructions {
+		if instruction.Opcode == LABEL || !instruction.HasLabelArgument() {
+			continue
+		}
+
+		if !labels[instruction.Argument] {
+			return fmt.Errorf("[#%d] undefined label L%d", i+1, instruction.Argument)
+		}
+	}
+
+	return nil
+}
+
+func NewVirtualMachineFromSource(source io.Reader, input io.Reader, output io.Writer) (*VirtualMachine, error) {
+	instructions, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVirtualMachine(instructions, input, output)
+}
+
+func (vm *VirtualMachine) Run() error {
+	err := vm.run()
+
+	if flushErr := vm.output.Flush(); flushErr != nil && err == nil {
+		err = fmt.Errorf("cannot write output, error: %v", flushErr)
+	}
+
+	return err
+}
+
+func (vm *VirtualMachine) run() error {
+	programCounter := 0
+
+	for programCounter < len(vm.instructions) {
+		instruction := vm.instructions[programCounter]
+		nextProgramCounter := programCounter + 1
+
+		var err error
+
+		switch instruction.Opcode {
+		case PUSH:
+			vm.push(instruction.Argument)
+		case DUPLICATE:
+			err = vm.copy(0)
+		case COPY:
+			err = vm.copy(instruction.Argument)
+		case SWAP:
+			err = vm.swap()
+		case DISCARD:
+			_, err = vm.pop()
+		case SLIDE:
+			err = vm.slide(instruction.Argument)
+		case ADD, SUBTRACT, MULTIPLY, DIVIDE, MOD:
+			err = vm.arithmetic(instruction.Opcode)
+		case STORE:
+			err = vm.store()
+		case RETRIEVE:
+			err = vm.retrieve()
+		case LABEL:
+		case CALL:
+			vm.callStack = append(vm.callStack, nextProgramCounter)
+			nextProgramCounter = vm.labels[instruction.Argument]
+		case JUMP:
+			nextProgramCounter = vm.labels[instruction.Argument]
+		case JUMP_IF_ZERO, JUMP_IF_NEGATIVE:
+			var value int64
+
+			value, err = vm.pop()
+			if err == nil && (instruction.Opcode == JUMP_IF_ZERO && value == 0 || instruction.Opcode == JUMP_IF_NEGATIVE && value < 0) {
+				nextProgramCounter = vm.labels[instruction.Argument]
+			}
+		case RETURN:
+			if len(vm.callStack) == 0 {
+				err = errors.New("return outside of subroutine")
+				break
+			}
+
+			nextProgramCounter = vm.callStack[len(vm.callStack)-1]
+			vm.callStack = vm.callStack[:len(vm.callStack)-1]
+		case END:
+			return nil
+		case PRINT_CHAR:
+			err = vm.printChar()
+		case PRINT_NUMBER:
+			err = vm.printNumber()
+		case READ_CHAR:
+			err = vm.readChar()
+		case READ_NUMBER:
+			err = vm.readNumber()
+		default:
+			err = fmt.Errorf("unknown opcode %q", instruction.Opcode)
+		}
+
+		if err != nil {
+			return fmt.Errorf("[#%d] %v", programCounter+1, err)
+		}
+
+		programCounter = nextProgramCounter
+	}
+
+	return errors.New("program terminated without end instruction")
+}
+
+func (vm *VirtualMachine) push(value int64) {
+	vm.stack = append(vm.stack, value)
+}
+
+func (vm *VirtualMachine) pop() (int64, error) {
+	if len(vm.stack) == 0 {
+		return 0, errors.New("stack underflow")
+	}
+
+	value := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+
+	return value, nil
+}
+
+func (vm *VirtualMachine) copy(itemOrdinalNumber int64) error {
+	if itemOrdinalNumber < 0 || itemOrdinalNumber >= int64(len(vm.stack)) {
+		return errors.New("stack underflow")
+	}
+
+	vm.push(vm.stack[int64(len(vm.stack))-1-itemOrdinalNumber])
+
+	return nil
+}
+
+func (vm *VirtualMachine) swap() error {
+	if len(vm.stack) < 2 {
+		return errors.New("stack underflow")
+	}
+
+	top := len(vm.stack) - 1
+	vm.stack[top], vm.stack[top-1] = vm.stack[top-1], vm.stack[top]
+
+	return nil
+}
+
+func (vm *VirtualMachine) slide(itemCount int64) error {
+	top, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	if itemCount < 0 || itemCount > int64(len(vm.stack)) {
+		itemCount = int64(len(vm.stack))
+	}
+
+	vm.stack = vm.stack[:int64(len(vm.stack))-itemCount]
+	vm.push(top)
+
+	return nil
+}
+
+func (vm *VirtualMachine) arithmetic(opcode Opcode) error {
+	right, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	left, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	result, err := arithmeticResult(opcode, left, right)
+	if err != nil {
+		return err
+	}
+
+	vm.push(result)
+
+	return nil
+}
+
+func arithmeticResult(opcode Opcode, left, right int64) (int64, error) {
+	switch opcode {
+	case ADD:
+		return left + right, nil
+	case SUBTRACT:
+		return left - right, nil
+	case MULTIPLY:
+		return left * right, nil
+	case DIVIDE, MOD:
+		if right == 0 {
+			return 0, errors.New("division by zero")
+		}
+
+		quotient := left / right
+		remainder := left % right
+
+		// Whitespace follows Haskell semantics, rounding the quotient towards negative infinity
+		if remainder != 0 && (remainder < 0) != (right < 0) {
+			quotient--
+			remainder += right
+		}
+
+		if opcode == DIVIDE {
+			return quotient, nil
+		}
+
+		return remainder, nil
+	default:
+		return 0, fmt.Errorf("unknown arithmetic opcode %q", opcode)
+	}
+}
+
+func (vm *VirtualMachine) store() error {
+	value, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	address, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	vm.heap[address] = value
+
+	return nil
+}
+
+func (vm *VirtualMachine) retrieve() error {
+	address, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	vm.push(vm.heap[address])
+
+	return nil
+}
+
+func (vm *VirtualMachine) printChar() error {
+	value, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	if _, err = vm.output.WriteRune(rune(value)); err != nil {
+		return fmt.Errorf("cannot write output, error: %v", err)
+	}
+
+	return nil
+}
+
+func (vm *VirtualMachine) printNumber() error {
+	value, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	if _, err = vm.output.WriteString(strconv.FormatInt(value, 10)); err != nil {
+		return fmt.Errorf("cannot write output, error: %v", err)
+	}
+
+	return nil
+}
+
+func (vm *VirtualMachine) readChar() error {
+	address, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	if err = vm.output.Flush(); err != nil {
+		return fmt.Errorf("cannot write output, error: %v", err)
+	}
+
+	char, _, err := vm.input.ReadRune()
+	if err != nil {
+		if err != io.EOF {
+			return fmt.Errorf("cannot read input, error: %v", err)
+		}
+
+		// Whitespace does not define the end of input, programs transpiled from Javascript expect -1 there
+		char = -1
+	}
+
+	vm.heap[address] = int64(char)
+
+	return nil
+}
+
+func (vm *VirtualMachine) readNumber() error {
+	address, err := vm.pop()
+	if err != nil {
+		return err
+	}
+
+	if err = vm.output.Flush(); err != nil {
+		return fmt.Errorf("cannot write output, error: %v", err)
+	}
+
+	line, err := vm.input.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return fmt.Errorf("cannot read number, error: %v", err)
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse %q as number", strings.TrimSpace(line))
+	}
+
+	vm.heap[address] = value
+
+	return nil
+}
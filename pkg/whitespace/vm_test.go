@@ -0,0 +1,195 @@
+package whitespace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func fromNotation(notation string) string {
+	return strings.Map(func(char rune) rune {
+		switch char {
+		case 'S':
+			return SPACE
+		case 'T':
+			return TAB
+		case 'L':
+			return LINE_FEED
+		default:
+			return -1
+		}
+	}, notation)
+}
+
+func TestVirtualMachine(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "print char",
+			source:         "SS STSSTSSSL TLSS LLL",
+			expectedOutput: "H",
+		},
+		{
+			name:           "arithmetic",
+			source:         "SS STTTL SS STSL TSSS TLST SS STSL SS STTL TSSL TLST SS STTTL SS STSL TSTS TLST SS TTTTL SS STSL TSTS TLST SS TTTTL SS STSL TSTT TLST LLL",
+			expectedOutput: "963-41",
+		},
+		{
+			name:           "stack manipulation",
+			source:         "SS STL SS STSL SLS TLST SLT TLST SS STTL STS STL TLST SLL SS STSSL SS STSTL STL STL TLST TLST LLL",
+			expectedOutput: "21252",
+		},
+		{
+			name:           "heap",
+			source:         "SS STL SS STSTSTSL TTS SS STL TTT TLSS LLL",
+			expectedOutput: "*",
+		},
+		{
+			name:           "subroutine call",
+			source:         "LSTSTL LSTSTL LLL LSSSTL SS STSSSSSTL TLSS LTL",
+			expectedOutput: "AA",
+		},
+		{
+			name:           "conditional jumps",
+			source:         "SS STTL LSSTL SLS TLST SS STL TSST SLS LTSTSL LSLTL LSSTSL LLL",
+			expectedOutput: "321",
+		},
+		{
+			name:           "read input",
+			source:         "SS STL TLTS SS STL TTT TLSS SS STSL TLTT SS STSL TTT TLST LLL",
+			input:          "x42\n",
+			expectedOutput: "x42",
+		},
+	}
+
+	for _, test := range tests {
+		var output bytes.Buffer
+
+		vm, err := NewVirtualMachineFromSource(strings.NewReader(fromNotation(test.source)), strings.NewReader(test.input), &output)
+		if err != nil {
+			t.Fatalf("%s: cannot load program, error: %v", test.name, err)
+		}
+
+		if err = vm.Run(); err != nil {
+			t.Fatalf("%s: runtime error: %v", test.name, err)
+		}
+
+		if output.String() != test.expectedOutput {
+			t.Errorf("%s: output incorrect. expected=%q, got=%q", test.name, test.expectedOutput, output.String())
+		}
+	}
+}
+
+func TestVirtualMachineInstructions(t *testing.T) {
+	instructions := []Instruction{
+		PushToStack(7),
+		PushToStack(-3),
+		Multiply(),
+		PrintTopStackInteger(),
+		EndProgram(),
+	}
+
+	var output bytes.Buffer
+
+	vm, err := NewVirtualMachine(instructions, strings.NewReader(""), &output)
+	if err != nil {
+		t.Fatalf("cannot load program, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("runtime error: %v", err)
+	}
+
+	if output.String() != "-21" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "-21", output.String())
+	}
+}
+
+func TestVirtualMachineIgnoresNonWhitespaceCharacters(t *testing.T) {
+	source := "let\u2007a=1;" + fromNotation("SS STSSSSSTL") + "//comment" + fromNotation("TLSS LLL") + "console.log(a);"
+
+	var output bytes.Buffer
+
+	vm, err := NewVirtualMachineFromSource(strings.NewReader(source), strings.NewReader(""), &output)
+	if err != nil {
+		t.Fatalf("cannot load program, error: %v", err)
+	}
+
+	if err = vm.Run(); err != nil {
+		t.Fatalf("runtime error: %v", err)
+	}
+
+	if output.String() != "A" {
+		t.Errorf("output incorrect. expected=%q, got=%q", "A", output.String())
+	}
+}
+
+func TestVirtualMachineErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "stack underflow", source: "TLST LLL"},
+		{name: "division by zero", source: "SS STL SS SL TSTS LLL"},
+		{name: "return outside of subroutine", source: "LTL"},
+		{name: "missing end", source: "SS STL"},
+	}
+
+	for _, test := range tests {
+		vm, err := NewVirtualMachineFromSource(strings.NewReader(fromNotation(test.source)), strings.NewReader(""), &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("%s: cannot load program, error: %v", test.name, err)
+		}
+
+		if err = vm.Run(); err == nil {
+			t.Errorf("%s: expected runtime error", test.name)
+		}
+	}
+
+	loadTests := []struct {
+		name   string
+		source string
+	}{
+		{name: "undefined label", source: "LSLTL LLL"},
+		{name: "duplicate label", source: "LSSTL LSSTL LLL"},
+		{name: "unknown instruction", source: "TTL"},
+		{name: "unterminated argument", source: "SS STT"},
+	}
+
+	for _, test := range loadTests {
+		if _, err := NewVirtualMachineFromSource(strings.NewReader(fromNotation(test.source)), strings.NewReader(""), &bytes.Buffer{}); err == nil {
+			t.Errorf("%s: expected load error", test.name)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions []Instruction
+		valid        bool
+	}{
+		{name: "valid program", instructions: []Instruction{Label(1), JumpToLabelIfZero(1), EndProgram()}, valid: true},
+		{name: "empty program", instructions: []Instruction{}, valid: false},
+		{name: "unknown opcode", instructions: []Instruction{{Opcode: "nop"}}, valid: false},
+		{name: "duplicate label", instructions: []Instruction{Label(1), Label(1)}, valid: false},
+		{name: "undefined label", instructions: []Instruction{CallSubroutine(2), EndProgram()}, valid: false},
+		{name: "negative compact label", instructions: []Instruction{{Opcode: LABEL, Argument: -1, CompactLabel: true}, EndProgram()}, valid: false},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.instructions)
+
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected validation error: %v", test.name, err)
+		}
+
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected validation error", test.name)
+		}
+	}
+}